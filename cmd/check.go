@@ -1,14 +1,25 @@
 package cmd
 
 import (
-	"encoding/json"
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/jimschubert/ossify/internal/baseline"
+	"github.com/jimschubert/ossify/internal/config"
 	"github.com/jimschubert/ossify/internal/config/conventions"
+	"github.com/jimschubert/ossify/internal/fix"
+	"github.com/jimschubert/ossify/internal/fleet"
 	"github.com/jimschubert/ossify/internal/model"
+	"github.com/jimschubert/ossify/internal/report"
+	"github.com/jimschubert/ossify/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +31,19 @@ type CheckFlags struct {
 	conventionFile string
 	directory      string
 	all            bool
+	format         string
+	output         string
+	fix            bool
+	dryRun         bool
+	gitKeep        bool
+	license        string
+	prohibited     string
+	moveTo         string
+	recursive      bool
+	repos          string
+	jobs           int
+	baseline       string
+	writeBaseline  string
 }
 
 func init() {
@@ -29,11 +53,37 @@ func init() {
 	checkCmd.Flags().StringVarP(&checkFlags.conventionID, "convention", "c", "",
 		"The ID/name of a convention to check against (e.g., 'Go', 'Standard Distribution')")
 	checkCmd.Flags().StringVarP(&checkFlags.conventionFile, "file", "f", "",
-		"Path to a JSON file describing the convention rules to check")
+		"Path to a JSON, YAML or TOML file describing the convention rules to check")
 	checkCmd.Flags().StringVarP(&checkFlags.directory, "directory", "d", ".",
 		"The directory to check (defaults to current directory)")
 	checkCmd.Flags().BoolVarP(&checkFlags.all, "all", "a", false,
 		"Check against all known conventions")
+	checkCmd.Flags().StringVar(&checkFlags.format, "format", string(report.Text),
+		fmt.Sprintf("Output format (%s)", strings.Join(report.Formats(), ", ")))
+	checkCmd.Flags().StringVarP(&checkFlags.output, "output", "o", "",
+		"Write results to `file` instead of stdout")
+	checkCmd.Flags().BoolVar(&checkFlags.fix, "fix", false,
+		"Create missing directories and files, and delete or move prohibited items")
+	checkCmd.Flags().BoolVar(&checkFlags.dryRun, "dry-run", false,
+		"Print the changes --fix would make without touching disk (implies --fix)")
+	checkCmd.Flags().BoolVar(&checkFlags.gitKeep, "gitkeep", false,
+		"Add a .gitkeep to directories created by --fix")
+	checkCmd.Flags().StringVar(&checkFlags.license, "license", "",
+		"License `id` used to generate a missing LICENSE with --fix")
+	checkCmd.Flags().StringVar(&checkFlags.prohibited, "prohibited", string(fix.Ask),
+		"What --fix does with prohibited items: ask, delete, move or skip (ask skips when stdin is not a terminal)")
+	checkCmd.Flags().StringVar(&checkFlags.moveTo, "move-to", ".ossify-removed",
+		"Directory, relative to the checked directory, into which --prohibited=move moves items")
+	checkCmd.Flags().BoolVarP(&checkFlags.recursive, "recursive", "r", false,
+		"Check every module (project root) under the directory against its own conventions")
+	checkCmd.Flags().StringVar(&checkFlags.repos, "repos", "",
+		"Check many repositories, listed one directory per line in a manifest `file` or matched by a glob, and summarize the fleet")
+	checkCmd.Flags().IntVarP(&checkFlags.jobs, "jobs", "j", runtime.NumCPU(),
+		"Number of repositories checked concurrently with --repos")
+	checkCmd.Flags().StringVar(&checkFlags.baseline, "baseline", "",
+		"Only fail on violations not recorded in the baseline `file` (e.g. "+baseline.DefaultFile+"), and report recorded violations which were fixed")
+	checkCmd.Flags().StringVar(&checkFlags.writeBaseline, "write-baseline", "",
+		"Record the current failures and warnings into a baseline `file` (e.g. "+baseline.DefaultFile+")")
 }
 
 var checkCmd = &cobra.Command{
@@ -44,12 +94,74 @@ var checkCmd = &cobra.Command{
 You can specify the convention to check against in several ways:
   1. By name as an argument: ossify check Go
   2. By name with --convention flag: ossify check -c "Standard Distribution"
-  3. By JSON file: ossify check -f my-convention.json
+  3. By file: ossify check -f my-convention.json (or .yaml, .toml)
   4. Check all conventions: ossify check --all
+  5. From the project: ossify check
+
+Without a convention, a project configuration (.ossify.json, .ossify.yaml or
+.ossify.yml) is looked up in the directory and its parents up to the
+repository root. It names the conventions to check, adds project rules,
+overrides rule levels and ignores rules:
+  {
+    "conventions": ["Go"],
+    "rules": [{ "level": "required", "type": "file", "value": "CODEOWNERS" }],
+    "overrides": { "docs": "preferred" },
+    "ignore": ["src"]
+  }
+
+When there is no project configuration, or it names no conventions, the
+directory is inspected for each convention's detection markers (e.g. go.mod
+for Go), and every matching convention is checked. When a convention and one
+it extends both match, only the extending convention is checked. The reason
+each convention was selected is printed before the results.
 
 The directory to check defaults to the current directory, but can be
 specified with the --directory flag.
 
+Use --repos to audit many repositories at once, given a manifest file listing
+one directory per line (relative to the manifest) or a glob of directories:
+  ossify check --repos repos.txt --jobs 8
+  ossify check --repos 'checkouts/*' -c Go --format json
+Repositories are checked concurrently, each against the given convention or
+else its own project conventions. The report summarizes the fleet: the result
+of each repository, the pass rate of each rule and the worst offenders. The
+junit, tap and sarif formats combine the results of every repository.
+
+Use --recursive to check a monorepo. Every directory in which a convention is
+detected is a module, checked against its own conventions, unless the project
+configuration maps module directories (or glob patterns) to conventions:
+  { "modules": { "services/*": ["Go"], "web": ["Node.js"], "tools/lint": [] } }
+A module with no conventions listed has them detected. A module's own
+.ossify.json names its conventions and rules; otherwise the nearest project
+configuration above it only adjusts rule levels and ignored rules. The report
+has a section per module and the check fails when any module fails.
+
+Results are printed as a table by default. Use --format to emit json, yaml,
+junit, tap or sarif for pipelines and dashboards, and --output to write the
+results to a file. The json and yaml documents carry a "version" field which changes
+only when existing fields are removed or change meaning.
+
+Use --fix to create missing required and preferred directories and files.
+LICENSE is generated from the license given by --license, README.md and
+CONTRIBUTING.md from built-in templates, and any other file is created empty.
+Prohibited items are deleted or moved according to --prohibited. Use --dry-run
+to print the planned change set without touching disk. After fixing, the
+directory is checked again and the results reflect the fixed state.
+
+A rule can be waived for a project in a .ossifyignore file, found in the
+directory or its parents up to the repository root. Each line names the
+convention ("*" for any), the rule value, a mandatory reason, and an optional
+expiry date after which the rule fails again:
+  Go | src | generated SDK is published from src/ | 2026-12-31
+Waived failures are reported as suppressed and do not fail the check.
+
+To adopt a convention in an existing project, use --write-baseline to record
+the current failures and warnings into a baseline file (.ossify-baseline.json
+by default), and commit it. Later runs with --baseline report the recorded
+violations as suppressed and fail only on new violations. Recorded violations
+which no longer occur are listed as fixed; run --write-baseline again to
+ratchet the baseline.
+
 Exit codes:
   0 - All required rules pass
   1 - One or more required rules failed`,
@@ -74,6 +186,25 @@ Exit codes:
 		if optionsCount > 1 {
 			cobra.CheckErr(fmt.Errorf("--file, --convention (or convention name argument), and --all are mutually exclusive"))
 		}
+		if checkFlags.recursive && optionsCount > 0 {
+			cobra.CheckErr(fmt.Errorf("--recursive selects the conventions of each module and cannot be combined with --file, --convention or --all"))
+		}
+		if checkFlags.repos != "" && (checkFlags.recursive || checkFlags.fix || checkFlags.dryRun || checkFlags.baseline != "" || checkFlags.writeBaseline != "") {
+			cobra.CheckErr(fmt.Errorf("--repos cannot be combined with --recursive, --fix, --dry-run, --baseline or --write-baseline"))
+		}
+		if checkFlags.baseline != "" && checkFlags.writeBaseline != "" {
+			cobra.CheckErr(fmt.Errorf("--baseline and --write-baseline are mutually exclusive"))
+		}
+
+		format, err := report.ParseFormat(checkFlags.format)
+		if err != nil {
+			cobra.CheckErr(err)
+		}
+
+		prohibitedMode, err := fix.ParseProhibitedMode(checkFlags.prohibited)
+		if err != nil {
+			cobra.CheckErr(err)
+		}
 
 		// Determine the target directory
 		targetDir := checkFlags.directory
@@ -99,7 +230,7 @@ Exit codes:
 		// Collect conventions to check
 		var conventionsToCheck []model.Convention
 
-		// Option 1: Load from JSON file
+		// Option 1: Load from a convention file
 		if checkFlags.conventionFile != "" {
 			convention, err := loadConventionFromFile(checkFlags.conventionFile)
 			if err != nil {
@@ -136,71 +267,529 @@ Exit codes:
 			conventionsToCheck = *allConventions
 		}
 
-		// If no convention specified, show help
-		if len(conventionsToCheck) == 0 {
-			fmt.Println("no convention specified")
-			fmt.Println()
-			_ = cmd.Help()
-			os.Exit(1)
+		// keep structured output parseable by sending informational messages to stderr
+		infoOutput := os.Stdout
+		if format != report.Text || checkFlags.output != "" {
+			infoOutput = os.Stderr
 		}
 
-		// Run checks
-		hasFailures := false
-		for i, convention := range conventionsToCheck {
-			if i > 0 {
-				fmt.Println("\n" + strings.Repeat("-", separatorWidth) + "\n")
+		// Check a fleet of repositories, against the given conventions or each repository's own
+		if checkFlags.repos != "" {
+			failed, err := checkRepos(conventionsToCheck, format)
+			if err != nil {
+				cobra.CheckErr(err)
+			}
+			if failed {
+				os.Exit(1)
 			}
+			return
+		}
 
-			result, err := convention.Evaluate(absDir)
+		// each module of a recursive check is checked against its own conventions
+		modules := []moduleCheck{{dir: absDir}}
+		if checkFlags.recursive {
+			modules, err = recursiveModules(absDir, infoOutput)
 			if err != nil {
-				cobra.CheckErr(fmt.Errorf("evaluating convention '%s': %w", convention.Name, err))
+				cobra.CheckErr(err)
+			}
+			if len(modules) == 0 {
+				_, _ = fmt.Fprintf(infoOutput, "no modules detected in %s\n", absDir)
+				os.Exit(1)
+			}
+		} else {
+			// Option 4: Use the project configuration, detecting conventions it does not name
+			if optionsCount == 0 {
+				conventionsToCheck, err = projectConventions(absDir, infoOutput)
+				if err != nil {
+					cobra.CheckErr(err)
+				}
 			}
 
-			result.Print()
+			// If no convention specified or detected, show help
+			if len(conventionsToCheck) == 0 {
+				_, _ = fmt.Fprintf(infoOutput, "no convention specified, and none detected in %s\n\n", absDir)
+				cmd.SetOut(infoOutput)
+				_ = cmd.Help()
+				os.Exit(1)
+			}
+			modules[0].conventions = conventionsToCheck
+		}
 
+		// Run checks
+		var results []*model.CheckResult
+		for _, module := range modules {
+			moduleResults, err := checkModule(module, prohibitedMode, format, infoOutput)
+			if err != nil {
+				cobra.CheckErr(err)
+			}
+			results = append(results, moduleResults...)
+		}
+
+		if err := applyBaseline(results, infoOutput); err != nil {
+			cobra.CheckErr(err)
+		}
+
+		hasFailures := false
+		for _, result := range results {
 			if result.HasFailures() {
 				hasFailures = true
 			}
 		}
 
+		if err := writeReport(format, checkFlags.output, results); err != nil {
+			cobra.CheckErr(fmt.Errorf("writing results: %w", err))
+		}
+
 		if hasFailures {
 			os.Exit(1)
 		}
 	},
 }
 
-const separatorWidth = 60
+// projectConventions selects the conventions for dir when none is given on the command line. A project
+// configuration (.ossify.json or .ossify.yaml) found at dir or the repository root names the conventions and
+// adjusts their rules; conventions it does not name are detected from the project's marker files.
+func projectConventions(dir string, w io.Writer) ([]model.Convention, error) {
+	allConventions, err := conventions.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading conventions: %w", err)
+	}
+
+	projectConfig, err := config.FindProjectConfig(dir, util.GitTopLevel(dir))
+	if err != nil {
+		return nil, fmt.Errorf("loading project configuration: %w", err)
+	}
+
+	selected, err := selectConventions(*allConventions, dir, projectConfig, false, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) > 0 {
+		_, _ = fmt.Fprintln(w)
+	}
+	return selected, nil
+}
+
+// selectConventions returns the conventions named by names, or else by projectConfig, or else detected in dir,
+// with projectConfig applied to them. A configuration inherited from a parent directory only adjusts the
+// rules; the conventions it names and its own rules belong to the parent.
+func selectConventions(all []model.Convention, dir string, projectConfig *config.ProjectConfig, inherited bool, names []string, w io.Writer) ([]model.Convention, error) {
+	source := "the project configuration"
+	if projectConfig != nil {
+		_, _ = fmt.Fprintf(w, "Using project configuration %s\n", projectConfig.Path)
+		source = projectConfig.Path
+		if len(names) == 0 && !inherited {
+			names = projectConfig.Conventions
+		}
+	}
+
+	var selected []model.Convention
+	for _, name := range names {
+		convention := findConventionByName(all, name)
+		if convention == nil {
+			return nil, fmt.Errorf("convention '%s' named in %s not found", name, source)
+		}
+		selected = append(selected, *convention)
+	}
+
+	if len(selected) == 0 {
+		detections, err := conventions.Detect(all, dir)
+		if err != nil {
+			return nil, fmt.Errorf("detecting conventions: %w", err)
+		}
+
+		if len(detections) > 0 {
+			_, _ = fmt.Fprintln(w, "Detected conventions:")
+			for _, d := range detections {
+				_, _ = fmt.Fprintf(w, "  - %s\n", d.Describe())
+				selected = append(selected, d.Convention)
+			}
+		}
+	}
+
+	if projectConfig != nil {
+		for i := range selected {
+			selected[i] = projectConfig.Apply(selected[i])
+		}
+		if own := projectConfig.Convention(); own != nil && !inherited {
+			selected = append(selected, *own)
+		}
+	}
+	return selected, nil
+}
+
+// moduleCheck is a directory and the conventions it is checked against
+type moduleCheck struct {
+	dir string
+	// module is the path of dir relative to the root of a recursive check, or empty
+	module      string
+	conventions []model.Convention
+}
+
+// checkModule evaluates the conventions of module, applying its suppressions and fixing it when --fix is set
+func checkModule(module moduleCheck, mode fix.ProhibitedMode, format report.Format, w io.Writer) ([]*model.CheckResult, error) {
+	suppressions, err := config.FindSuppressions(module.dir, util.GitTopLevel(module.dir))
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", config.IgnoreFileName, err)
+	}
+
+	results, err := evaluateConventions(module.conventions, module.dir, suppressions)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkFlags.fix || checkFlags.dryRun {
+		if module.module != "" {
+			_, _ = fmt.Fprintf(w, "Fixing module %s\n", module.module)
+		}
+		applied, err := fixResults(module.dir, results, mode, w)
+		if err != nil {
+			return nil, fmt.Errorf("fixing: %w", err)
+		}
+
+		if applied {
+			results, err = evaluateConventions(module.conventions, module.dir, suppressions)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if format == report.Text && checkFlags.output == "" {
+			_, _ = fmt.Fprintln(w)
+		}
+	}
+
+	for _, result := range results {
+		result.Module = module.module
+	}
+	return results, nil
+}
+
+// recursiveModules finds the modules of the monorepo rooted at root. When the project configuration maps
+// module directories to conventions, those modules are checked; otherwise every directory in which a
+// convention is detected is a module. Each module's own project configuration, or the nearest one between the
+// module and root, adjusts its conventions.
+func recursiveModules(root string, w io.Writer) ([]moduleCheck, error) {
+	allConventions, err := conventions.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading conventions: %w", err)
+	}
+
+	rootConfig, err := config.FindProjectConfig(root, util.GitTopLevel(root))
+	if err != nil {
+		return nil, fmt.Errorf("loading project configuration: %w", err)
+	}
+
+	// configured maps module directories to the conventions named for them
+	configured := make(map[string][]string)
+	var dirs []string
+	if rootConfig != nil && len(rootConfig.Modules) > 0 {
+		_, _ = fmt.Fprintf(w, "Using modules of project configuration %s\n", rootConfig.Path)
+		configured, err = rootConfig.ModuleDirs()
+		if err != nil {
+			return nil, err
+		}
+		for dir := range configured {
+			if rel, err := filepath.Rel(root, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				dirs = append(dirs, dir)
+			}
+		}
+		slices.Sort(dirs)
+	} else {
+		detected, err := conventions.DetectModules(*allConventions, root)
+		if err != nil {
+			return nil, fmt.Errorf("detecting modules: %w", err)
+		}
+		for _, m := range detected {
+			dirs = append(dirs, filepath.Join(root, filepath.FromSlash(m.Path)))
+		}
+	}
+
+	modules := make([]moduleCheck, 0, len(dirs))
+	for _, dir := range dirs {
+		rel, _ := filepath.Rel(root, dir)
+		module := filepath.ToSlash(rel)
+
+		projectConfig, err := config.FindProjectConfig(dir, root)
+		if err != nil {
+			return nil, fmt.Errorf("loading project configuration: %w", err)
+		}
+
+		inherited := projectConfig != nil && filepath.Dir(projectConfig.Path) != dir
+		var info bytes.Buffer
+		selected, err := selectConventions(*allConventions, dir, projectConfig, inherited, configured[dir], &info)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module, err)
+		}
+		if len(selected) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(w, "Module %s:\n", module)
+		if names := configured[dir]; len(names) > 0 {
+			_, _ = fmt.Fprintf(w, "  Configured conventions: %s\n", strings.Join(names, ", "))
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(info.String(), "\n"), "\n") {
+			_, _ = fmt.Fprintf(w, "  %s\n", line)
+		}
+		modules = append(modules, moduleCheck{dir: dir, module: module, conventions: selected})
+	}
+
+	if len(modules) > 0 {
+		_, _ = fmt.Fprintln(w)
+	}
+	return modules, nil
+}
+
+// evaluateConventions checks dir against each convention, waiving failures covered by suppressions
+func evaluateConventions(conventionsToCheck []model.Convention, dir string, suppressions []model.Suppression) ([]*model.CheckResult, error) {
+	now := time.Now()
+	results := make([]*model.CheckResult, 0, len(conventionsToCheck))
+	for _, convention := range conventionsToCheck {
+		result, err := convention.Evaluate(dir)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating convention '%s': %w", convention.Name, err)
+		}
+		result.Suppress(suppressions, now)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// applyBaseline records results into the file given by --write-baseline, or waives the failures recorded in
+// the file given by --baseline and prints the recorded failures which were fixed to w
+func applyBaseline(results []*model.CheckResult, w io.Writer) error {
+	if checkFlags.writeBaseline != "" {
+		b := baseline.New(results)
+		if err := b.Write(checkFlags.writeBaseline); err != nil {
+			return fmt.Errorf("writing baseline: %w", err)
+		}
+		_, _ = fmt.Fprintf(w, "Wrote %d violation(s) to baseline %s\n\n", len(b.Entries), checkFlags.writeBaseline)
+		b.Apply(results, checkFlags.writeBaseline)
+		return nil
+	}
+
+	if checkFlags.baseline == "" {
+		return nil
+	}
+
+	b, err := baseline.Load(checkFlags.baseline)
+	if err != nil {
+		return fmt.Errorf("loading baseline: %w", err)
+	}
+	fixed := b.Apply(results, checkFlags.baseline)
+	if len(fixed) > 0 {
+		_, _ = fmt.Fprintln(w, "Fixed since baseline:")
+		for _, entry := range fixed {
+			_, _ = fmt.Fprintf(w, "  - %s\n", entry)
+		}
+		_, _ = fmt.Fprintf(w, "Run with --write-baseline=%s to remove them from the baseline.\n\n", checkFlags.baseline)
+	}
+	return nil
+}
+
+// fixResults plans the changes which fix results and prints them to w. Unless --dry-run is set, the changes
+// are applied and true is returned.
+func fixResults(dir string, results []*model.CheckResult, mode fix.ProhibitedMode, w io.Writer) (bool, error) {
+	conf, err := config.ConfigManager.Load()
+	if err != nil {
+		return false, fmt.Errorf("loading config: %w", err)
+	}
+
+	project := filepath.Base(dir)
+	if topLevel := util.GitTopLevel(dir); topLevel != "" {
+		project = filepath.Base(topLevel)
+	}
+	values, err := licenseValues(&LicenseFlags{project: project}, conf)
+	if err != nil {
+		return false, err
+	}
+
+	opts := fix.Options{
+		GitKeep:         checkFlags.gitKeep,
+		License:         checkFlags.license,
+		LicenseLocation: conf.LicensePath,
+		Values:          *values,
+		Prohibited:      mode,
+		MoveTo:          checkFlags.moveTo,
+	}
+	if mode == fix.Ask && !checkFlags.dryRun && isTerminal(os.Stdin) {
+		opts.Ask = askProhibited(bufio.NewReader(os.Stdin), w)
+	}
+
+	actions, skipped, err := fix.Plan(dir, results, opts)
+	if err != nil {
+		return false, err
+	}
+
+	fix.PrintPlan(w, actions, skipped)
+	if checkFlags.dryRun || len(actions) == 0 {
+		return false, nil
+	}
+
+	if err := fix.Apply(dir, actions); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// askProhibited prompts for what to do with each prohibited item
+func askProhibited(in *bufio.Reader, out io.Writer) func(string) fix.ProhibitedMode {
+	return func(path string) fix.ProhibitedMode {
+		for {
+			_, _ = fmt.Fprintf(out, "%s is prohibited: [d]elete, [m]ove or [s]kip? ", path)
+			answer, err := in.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "d", "delete":
+				return fix.DeleteItems
+			case "m", "move":
+				return fix.MoveItems
+			case "s", "skip":
+				return fix.SkipItems
+			}
+			if err != nil {
+				return fix.SkipItems
+			}
+		}
+	}
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// checkRepos checks each repository named by --repos concurrently, against conventionsToCheck or else the
+// conventions of the repository's project configuration or detected in it, and writes the fleet report. It
+// returns whether any repository failed or could not be checked.
+func checkRepos(conventionsToCheck []model.Convention, format report.Format) (bool, error) {
+	dirs, err := fleet.Directories(checkFlags.repos)
+	if err != nil {
+		return false, err
+	}
 
-// loadConventionFromFile loads and validates a convention from a JSON file.
-// If the convention has no name, the filename is used as the name.
+	repos := fleet.Check(dirs, checkFlags.jobs, func(dir string) ([]*model.CheckResult, error) {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(absDir); err != nil {
+			return nil, err
+		} else if !info.IsDir() {
+			return nil, fmt.Errorf("'%s' is not a directory", absDir)
+		}
+
+		selected := conventionsToCheck
+		if len(selected) == 0 {
+			if selected, err = projectConventions(absDir, io.Discard); err != nil {
+				return nil, err
+			}
+			if len(selected) == 0 {
+				return nil, fmt.Errorf("no convention detected")
+			}
+		}
+
+		suppressions, err := config.FindSuppressions(absDir, util.GitTopLevel(absDir))
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", config.IgnoreFileName, err)
+		}
+		return evaluateConventions(selected, absDir, suppressions)
+	})
+
+	failed := false
+	for _, repo := range repos {
+		if repo.Failed() {
+			failed = true
+		}
+		// formats other than the fleet summary have no place for repositories which could not be checked
+		if repo.Err != nil && format != report.Text && format != report.JSON && format != report.YAML {
+			_, _ = fmt.Fprintf(os.Stderr, "%s: %v\n", repo.Directory, repo.Err)
+		}
+	}
+
+	err = writeOutput(checkFlags.output, func(w io.Writer) error {
+		return report.WriteFleet(w, format, repos)
+	})
+	if err != nil {
+		return false, fmt.Errorf("writing results: %w", err)
+	}
+	return failed, nil
+}
+
+// writeReport writes results in the given format to outputPath, or to stdout when outputPath is empty
+func writeReport(format report.Format, outputPath string, results []*model.CheckResult) error {
+	return writeOutput(outputPath, func(w io.Writer) error {
+		return report.Write(w, format, results)
+	})
+}
+
+// writeOutput calls write with outputPath, or with stdout when outputPath is empty
+func writeOutput(outputPath string, write func(io.Writer) error) error {
+	if outputPath == "" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadConventionFromFile loads and validates a convention from a JSON, YAML or TOML file.
+// If the convention has no name, the filename is used as the name. Conventions it extends are
+// resolved against the known conventions.
 func loadConventionFromFile(filePath string) (*model.Convention, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	var convention model.Convention
-	if err := json.Unmarshal(data, &convention); err != nil {
-		return nil, fmt.Errorf("invalid JSON: %w", err)
+	decoded, err := conventions.Decode(filePath, data)
+	if err != nil {
+		return nil, err
+	}
+	// the published schema is checked, so that errors match those of editors using it
+	if diagnostics := conventions.ValidateSchema(filePath, data); len(diagnostics) > 0 {
+		return nil, &conventions.ValidationError{Diagnostics: diagnostics}
 	}
+	convention := *decoded
 
 	if convention.Name == "" {
 		convention.Name = filepath.Base(filePath)
 	}
 
-	if len(convention.Rules) == 0 {
+	if len(convention.Rules) == 0 && len(convention.Extends) == 0 {
 		return nil, fmt.Errorf("convention must have at least one rule")
 	}
 
+	if len(convention.Extends) > 0 {
+		allConventions, err := conventions.Load()
+		if err != nil {
+			return nil, fmt.Errorf("loading conventions: %w", err)
+		}
+		resolved, err := conventions.Resolve(append(*allConventions, convention))
+		if err != nil {
+			return nil, err
+		}
+		convention = resolved[len(resolved)-1]
+	}
+
 	return &convention, nil
 }
 
-// findConventionByName searches for a convention by name (case-insensitive).
+// findConventionByName searches for a convention by name (case-insensitive). When several conventions share
+// the name, the last one wins, so that a custom convention takes precedence over a built-in one.
 // Returns nil if no matching convention is found.
 func findConventionByName(conventions []model.Convention, name string) *model.Convention {
 	nameLower := strings.ToLower(name)
-	for _, c := range conventions {
-		if strings.ToLower(c.Name) == nameLower {
+	for i := len(conventions) - 1; i >= 0; i-- {
+		if strings.ToLower(conventions[i].Name) == nameLower {
+			c := conventions[i]
 			return &c
 		}
 	}
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/jimschubert/ossify/internal/config"
 	"github.com/jimschubert/ossify/internal/model"
 )
 
@@ -65,6 +67,28 @@ func TestCheckCmd_Help(t *testing.T) {
 	}
 }
 
+func TestCheckCmd_BaselineFlags(t *testing.T) {
+	t.Cleanup(func() {
+		checkFlags.baseline, checkFlags.writeBaseline = "", ""
+	})
+
+	// the baseline path is the flag's value rather than the convention name
+	flags := checkCmd.Flags()
+	if err := flags.Parse([]string{"--baseline", "ci/baseline.json", "Go"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checkFlags.baseline != "ci/baseline.json" {
+		t.Errorf("baseline = %q, want ci/baseline.json", checkFlags.baseline)
+	}
+	if args := flags.Args(); !reflect.DeepEqual(args, []string{"Go"}) {
+		t.Errorf("args = %v, want [Go]", args)
+	}
+
+	if err := flags.Parse([]string{"--write-baseline"}); err == nil {
+		t.Errorf("expected an error for --write-baseline without a file")
+	}
+}
+
 func TestLoadConventionFromFile(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -106,6 +130,17 @@ func TestLoadConventionFromFile(t *testing.T) {
 			wantErr:     true,
 			errContains: "invalid JSON",
 		},
+		{
+			name: "unknown field",
+			content: `{
+				"name": "Typo",
+				"rules": [
+					{"level": "required", "type": "file", "value": "README.md", "matchs": "^# "}
+				]
+			}`,
+			wantErr:     true,
+			errContains: `$.rules[0].matchs: unknown field "matchs"`,
+		},
 		{
 			name: "empty rules",
 			content: `{
@@ -184,6 +219,7 @@ func TestFindConventionByName(t *testing.T) {
 		{Name: "Go", Rules: []model.Rule{{Level: model.Required, Type: model.File, Value: "go.mod"}}},
 		{Name: "Standard Distribution", Rules: []model.Rule{{Level: model.Required, Type: model.Directory, Value: "src"}}},
 		{Name: "Node.js", Rules: []model.Rule{{Level: model.Required, Type: model.File, Value: "package.json"}}},
+		{Name: "node.js", Rules: []model.Rule{{Level: model.Required, Type: model.File, Value: "deno.json"}}},
 	}
 
 	tests := []struct {
@@ -192,6 +228,7 @@ func TestFindConventionByName(t *testing.T) {
 		wantFound bool
 		wantName  string
 	}{
+		{"last of the same name wins", "Node.js", true, "node.js"},
 		{"exact match", "Go", true, "Go"},
 		{"case insensitive", "go", true, "Go"},
 		{"case insensitive upper", "GO", true, "Go"},
@@ -339,3 +376,157 @@ func TestCheckCmd_Integration(t *testing.T) {
 		t.Errorf("expected 4 passes, got %d", result.PassCount)
 	}
 }
+
+func TestProjectConventions(t *testing.T) {
+	originalManager := config.ConfigManager
+	config.ConfigManager = &config.Manager{
+		Load: func() (*config.Config, error) { return &config.Config{ConventionPath: t.TempDir()}, nil },
+		Save: func(c *config.Config) error { return nil },
+	}
+	defer func() { config.ConfigManager = originalManager }()
+
+	tests := []struct {
+		name      string
+		structure map[string]bool
+		project   string
+		want      []string
+		wantRules map[string]model.StrictnessLevel
+		wantErr   bool
+	}{
+		{
+			name:      "detects conventions without a project configuration",
+			structure: map[string]bool{"go.mod": false},
+			want:      []string{"Go"},
+		},
+		{
+			name:      "uses conventions named by the project",
+			structure: map[string]bool{"go.mod": false},
+			project:   `{"conventions": ["Rust"], "overrides": {"README.md": "preferred"}, "ignore": ["target/"]}`,
+			want:      []string{"Rust"},
+			wantRules: map[string]model.StrictnessLevel{"README.md": model.Preferred, "Cargo.toml": model.Required},
+		},
+		{
+			name:      "adds project rules to detected conventions",
+			structure: map[string]bool{"package.json": false},
+			project:   `{"rules": [{"level": "required", "type": "file", "value": "CODEOWNERS"}]}`,
+			want:      []string{"Node.js", ".ossify.json"},
+		},
+		{
+			name:    "reports unknown conventions",
+			project: `{"conventions": ["Cobol"]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupTestDirectory(t, tt.structure)
+			defer func() { _ = os.RemoveAll(dir) }()
+			if tt.project != "" {
+				if err := os.WriteFile(filepath.Join(dir, ".ossify.json"), []byte(tt.project), 0644); err != nil {
+					t.Fatalf("failed to write project configuration: %v", err)
+				}
+			}
+
+			var out bytes.Buffer
+			got, err := projectConventions(dir, &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("projectConventions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			names := make([]string, len(got))
+			for i, c := range got {
+				names[i] = c.Name
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("projectConventions() = %v, want %v\n%s", names, tt.want, out.String())
+			}
+
+			for _, c := range got {
+				for _, r := range c.Rules {
+					if r.Value == "target/" {
+						t.Errorf("ignored rule %s was not removed", r.Value)
+					}
+					if level, ok := tt.wantRules[r.Value]; ok && r.Level != level {
+						t.Errorf("rule %s level = %s, want %s", r.Value, r.Level, level)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRecursiveModules(t *testing.T) {
+	originalManager := config.ConfigManager
+	config.ConfigManager = &config.Manager{
+		Load: func() (*config.Config, error) { return &config.Config{ConventionPath: t.TempDir()}, nil },
+		Save: func(c *config.Config) error { return nil },
+	}
+	defer func() { config.ConfigManager = originalManager }()
+
+	tests := []struct {
+		name      string
+		structure map[string]bool
+		files     map[string]string
+		want      map[string][]string
+	}{
+		{
+			name: "detects modules by markers",
+			structure: map[string]bool{
+				"services/api/go.mod":       false,
+				"web/package.json":          false,
+				"tools/lint/pyproject.toml": false,
+				"docs":                      true,
+			},
+			want: map[string][]string{"services/api": {"Go"}, "web": {"Node.js"}, "tools/lint": {"Python"}},
+		},
+		{
+			name: "uses modules mapped by the project configuration",
+			structure: map[string]bool{
+				"services/api/go.mod":    false,
+				"services/worker/go.mod": false,
+				"web/package.json":       false,
+			},
+			files: map[string]string{
+				".ossify.json":     `{"modules": {"services/*": ["Go"], "web": []}, "rules": [{"level": "required", "type": "file", "value": "CODEOWNERS"}]}`,
+				"web/.ossify.json": `{"conventions": ["Standard Distribution"]}`,
+			},
+			want: map[string][]string{
+				"services/api":    {"Go"},
+				"services/worker": {"Go"},
+				"web":             {"Standard Distribution"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupTestDirectory(t, tt.structure)
+			defer func() { _ = os.RemoveAll(dir) }()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", name, err)
+				}
+			}
+
+			var out bytes.Buffer
+			modules, err := recursiveModules(dir, &out)
+			if err != nil {
+				t.Fatalf("recursiveModules() error = %v", err)
+			}
+
+			got := make(map[string][]string)
+			for _, m := range modules {
+				for _, c := range m.conventions {
+					got[m.module] = append(got[m.module], c.Name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recursiveModules() = %v, want %v\n%s", got, tt.want, out.String())
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -17,7 +20,11 @@ import (
 var conventionFlags *ConventionFlags
 
 type ConventionFlags struct {
-	id string
+	id         string
+	name       string
+	dryRun     bool
+	thresholds conventions.Thresholds
+	force      bool
 }
 
 func init() {
@@ -27,17 +34,36 @@ func init() {
 	// convention
 	conventionCmd.AddCommand(addConventionCmd)
 	conventionCmd.AddCommand(listConventionCmd)
+	conventionCmd.AddCommand(inferConventionCmd)
+	conventionCmd.AddCommand(showConventionCmd)
+	conventionCmd.AddCommand(removeConventionCmd)
+	conventionCmd.AddCommand(editConventionCmd)
+	conventionCmd.AddCommand(validateConventionCmd)
 
 	// convention add
 	addConventionCmd.Flags().StringVarP(&conventionFlags.id, "id", "i", "",
 		"The identifier to be associated with your customized convention. This will take precedence over a built-in convention with the same id.")
+	addConventionCmd.Flags().BoolVar(&conventionFlags.force, "force", false,
+		"Replace an existing custom convention with the same id")
+
+	// convention infer
+	inferConventionCmd.Flags().StringVarP(&conventionFlags.id, "id", "i", "",
+		"The identifier (file name) of the inferred convention; defaults to its name")
+	inferConventionCmd.Flags().StringVarP(&conventionFlags.name, "name", "n", "",
+		"The name of the inferred convention; defaults to the name of the directory")
+	inferConventionCmd.Flags().BoolVar(&conventionFlags.dryRun, "dry-run", false,
+		"Print the inferred convention as JSON instead of adding it")
+	inferConventionCmd.Flags().Float64Var(&conventionFlags.thresholds.Required, "required", conventions.DefaultThresholds.Required,
+		"Fraction of the directories which must have an item for it to be required")
+	inferConventionCmd.Flags().Float64Var(&conventionFlags.thresholds.Preferred, "preferred", conventions.DefaultThresholds.Preferred,
+		"Fraction of the directories which must have an item for it to be preferred; items in fewer are optional")
 }
 
 var conventionCmd = &cobra.Command{
 	Use:   "convention",
 	Short: "Manage file and structure conventions",
 	Run: func(cmd *cobra.Command, args []string) {
-		// TODO
+		_ = cmd.Help()
 	},
 }
 
@@ -47,38 +73,91 @@ var addConventionCmd = &cobra.Command{
 	Short: "Adds a new custom convention (local-only) to the list of known conventions",
 	Long: `Adds a new custom convention to your local configuration.
 
-The convention can be provided as a JSON file path, or piped via stdin.
-If --id is provided, it will be used as the filename; otherwise the convention's name is used.
+The convention can be provided as a JSON, YAML or TOML file path, or piped via
+stdin. The format is chosen by the file extension (.json, .yaml, .yml or .toml),
+otherwise by the content. YAML and TOML files are copied as-is, keeping their
+comments. If --id is provided, it will be used as the filename; otherwise the
+convention's name is used. An existing custom convention with the same id is
+only replaced with --force.
 
 Example JSON format:
 {
   "name": "My Convention",
   "rules": [
     { "level": "required", "type": "directory", "value": "src" },
-    { "level": "optional", "type": "file", "value": "CONTRIBUTING.md" }
+    { "level": "optional", "type": "file", "value": "CONTRIBUTING.md" },
+    { "level": "required", "type": "content", "value": "README.md", "matches": "^#+\\s*Installation" },
+    { "level": "prohibited", "type": "content", "value": "LICENSE", "contains": "<COPYRIGHT HOLDER>" }
   ]
 }
 
+A convention can inherit the rules of other conventions (built-in or custom) by
+name with "extends". Rules targeting the same value override inherited rules,
+and "remove" drops inherited rules by value:
+{
+  "name": "Our Go",
+  "extends": ["Go", "our-base"],
+  "remove": ["docs"],
+  "rules": [
+    { "level": "required", "type": "file", "value": "CODEOWNERS" }
+  ]
+}
+
+A convention with "detect" markers (patterns such as "go.mod" or "*.csproj") is
+selected automatically by "ossify check" when any marker exists. A convention
+extending another inherits its markers unless it declares its own.
+
 Valid levels: prohibited, optional, preferred, required
-Valid types: directory, file, pattern`,
-	Run: func(cmd *cobra.Command, args []string) {
-		conf, err := config.ConfigManager.Load()
-		failOnError(err)
+Valid types: directory, file, pattern, content
 
-		conventionsPath := conf.ConventionPath
-		if conventionsPath == "" {
-			fmt.Println("invalid conventions path: please update your configuration and try again")
-			os.Exit(1)
-		}
+Any rule may explain itself with a stable "id", a "description", a "rationale"
+link and "remediation" text, which are shown when it fails:
+    { "id": "license-present", "level": "required", "type": "file", "value": "LICENSE",
+      "description": "Every repository has a license",
+      "rationale": "https://opensource.guide/legal/",
+      "remediation": "run ` + "`ossify license MIT > LICENSE`" + `" }
 
-		err = os.MkdirAll(conventionsPath, 0755)
-		failOnError(err)
+Directory, file and pattern rules accept "alternatives" in place of "value":
+any one present satisfies the rule, and a prohibited rule forbids all of them:
+    { "level": "required", "type": "file", "value": "README.md", "alternatives": ["README.rst", "README"] }
+
+A rule with "when" applies only while its condition holds, and is skipped otherwise.
+The condition is a directory, file or pattern which exists, or content in a file:
+    { "level": "required", "type": "file", "value": ".dockerignore",
+      "when": { "type": "file", "value": "Dockerfile" } }
+
+Content rules check the file named by "value" for either a literal ("contains")
+or a regular expression ("matches"), evaluated line by line.
 
+Pattern rules use globs where "**" matches any number of directories and a
+trailing "/" matches only directories. Paths ignored by .gitignore and the .git
+directory are skipped. "exclude" lists patterns whose matches are ignored, and
+"each" names a path which must exist relative to every match:
+    { "level": "prohibited", "type": "pattern", "value": "**/*.orig", "exclude": ["testdata/"] },
+    { "level": "required", "type": "pattern", "value": "cmd/*/", "each": "main.go" }
+
+The same convention in YAML:
+name: My Convention
+rules:
+  - { level: required, type: directory, value: src }
+  # contributions are welcome, but a guide is not required
+  - { level: optional, type: file, value: CONTRIBUTING.md }
+
+and in TOML:
+name = "My Convention"
+
+[[rules]]
+level = "required"
+type = "directory"
+value = "src"`,
+	Run: func(cmd *cobra.Command, args []string) {
 		var data []byte
+		var err error
+		var filePath string
 
 		if len(args) == 1 {
 			// Read from file
-			filePath := args[0]
+			filePath = args[0]
 			data, err = os.ReadFile(filePath)
 			if err != nil {
 				fmt.Printf("failed to read file %s: %v\n", filePath, err)
@@ -88,7 +167,7 @@ Valid types: directory, file, pattern`,
 			// Read from stdin
 			stat, _ := os.Stdin.Stat()
 			if (stat.Mode() & os.ModeCharDevice) != 0 {
-				fmt.Println("no input provided: specify a file path or pipe JSON via stdin")
+				fmt.Println("no input provided: specify a file path or pipe a convention via stdin")
 				_ = cmd.Help()
 				os.Exit(1)
 			}
@@ -97,54 +176,181 @@ Valid types: directory, file, pattern`,
 		}
 
 		// Parse and validate the convention
-		var convention model.Convention
-		err = json.Unmarshal(data, &convention)
+		convention, err := conventions.Decode(filePath, data)
 		if err != nil {
-			fmt.Printf("invalid convention JSON: %v\n", err)
+			fmt.Printf("invalid convention: %v\n", err)
 			os.Exit(1)
 		}
 
-		if convention.Name == "" {
-			fmt.Println("convention must have a name")
-			os.Exit(1)
-		}
+		filename, err := saveConventionDocument(*convention, data, conventions.DetectFormat(filePath, data), filePath, conventionFlags.id, conventionFlags.force)
+		failOnError(err)
 
-		if len(convention.Rules) == 0 {
-			fmt.Println("convention must have at least one rule")
-			os.Exit(1)
-		}
+		fmt.Printf("convention '%s' saved to %s\n", convention.Name, filename)
+	},
+}
 
-		// Determine the filename
-		id := conventionFlags.id
-		if id == "" {
-			id = convention.Name
-		}
+var inferConventionCmd = &cobra.Command{
+	Use:   "infer <dir>...",
+	Args:  cobra.MinimumNArgs(1),
+	Short: "Proposes a convention from existing reference repositories and adds it",
+	Long: `Scans the top level of one or more reference repositories and proposes a
+convention reflecting what they have in common:
+  - directories and well-known files (README.md, LICENSE, CONTRIBUTING.md,
+    go.mod, ...) present in at least the --required fraction of the
+    repositories (default all) are required, those present in at least the
+    --preferred fraction (default half) are preferred, and those present in
+    fewer are optional
+  - known junk none of them has (build outputs, IDE folders, .DS_Store, ...)
+    is prohibited
+The .git directory and paths ignored by .gitignore are not considered present.
 
-		// Sanitize the id for use as a filename
-		id = strings.ReplaceAll(id, " ", "-")
-		id = strings.ReplaceAll(id, "/", "-")
-		id = strings.ToLower(id)
+  ossify convention infer ../service-a ../service-b ../service-c --name "Our Service" --required 0.9
 
-		filename := filepath.Join(conventionsPath, id+".json")
+A convention inferred from a single directory is named after it unless --name
+is given; --name is required for several directories. The convention is saved
+like "ossify convention add". Use --dry-run to print the proposed convention
+as JSON instead, e.g. to edit it before adding it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dirs := make([]string, 0, len(args))
+		for _, arg := range args {
+			dir, err := filepath.Abs(arg)
+			failOnError(err)
+			info, err := os.Stat(dir)
+			failOnError(err)
+			if !info.IsDir() {
+				failOnError(fmt.Errorf("'%s' is not a directory", dir))
+			}
+			dirs = append(dirs, dir)
+		}
 
-		// Check if file already exists
-		if _, err := os.Stat(filename); err == nil {
-			fmt.Printf("convention '%s' already exists at %s\n", id, filename)
-			fmt.Println("use a different --id or remove the existing file")
-			os.Exit(1)
+		name := conventionFlags.name
+		if name == "" {
+			if len(dirs) > 1 {
+				failOnError(fmt.Errorf("--name is required when inferring from several directories"))
+			}
+			name = filepath.Base(dirs[0])
 		}
 
-		// Marshal with indentation for readability
-		output, err := json.MarshalIndent(convention, "", "  ")
+		convention, err := conventions.Infer(name, dirs, conventionFlags.thresholds)
 		failOnError(err)
 
-		err = os.WriteFile(filename, output, 0644)
+		if conventionFlags.dryRun {
+			output, err := json.MarshalIndent(convention, "", "  ")
+			failOnError(err)
+			fmt.Println(string(output))
+			return
+		}
+
+		filename, err := saveConvention(*convention, conventionFlags.id, false)
 		failOnError(err)
 
-		fmt.Printf("convention '%s' saved to %s\n", convention.Name, filename)
+		fmt.Printf("convention '%s' with %d rules saved to %s\n", convention.Name, len(convention.Rules), filename)
 	},
 }
 
+// validateConvention reports whether the convention document data, read from source, can be saved in place of
+// the files replacing: it must be valid like every loaded convention, and extend only known conventions without
+// cycles. The replaced files are not loaded, so that an invalid file can be fixed.
+func validateConvention(source string, data []byte, replacing ...string) error {
+	if diagnostics := conventions.Errors(conventions.Validate(source, data)); len(diagnostics) > 0 {
+		return &conventions.ValidationError{Diagnostics: diagnostics}
+	}
+	convention, err := conventions.Decode(source, data)
+	if err != nil {
+		return fmt.Errorf("invalid convention: %w", err)
+	}
+
+	if len(convention.Extends) > 0 {
+		known, err := conventions.LoadExcept(replacing...)
+		if err != nil {
+			return err
+		}
+		if _, err := conventions.Resolve(append(*known, *convention)); err != nil {
+			return fmt.Errorf("invalid convention: %w", err)
+		}
+	}
+	return nil
+}
+
+// saveConvention validates convention and writes it to the conventions directory as id.json, or as its name
+// when id is empty. An existing convention file is only replaced when force is set. It returns the path of the
+// written file.
+func saveConvention(convention model.Convention, id string, force bool) (string, error) {
+	// Marshal with indentation for readability
+	output, err := json.MarshalIndent(convention, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return saveConventionDocument(convention, output, conventions.JSON, "", id, force)
+}
+
+// saveConventionDocument validates convention and writes its document data, in format, to the conventions
+// directory like saveConvention. The document is copied as-is, so that YAML and TOML comments are kept. Problems
+// in the document are reported against source, the file it was read from, or else the file it is written to.
+func saveConventionDocument(convention model.Convention, data []byte, format conventions.Format, source string, id string, force bool) (string, error) {
+	conf, err := config.ConfigManager.Load()
+	if err != nil {
+		return "", err
+	}
+
+	conventionsPath := conf.ConventionPath
+	if conventionsPath == "" {
+		return "", fmt.Errorf("invalid conventions path: please update your configuration and try again")
+	}
+
+	if err := os.MkdirAll(conventionsPath, 0755); err != nil {
+		return "", err
+	}
+
+	// Determine the filename
+	if id == "" {
+		id = convention.Name
+	}
+
+	// Sanitize the id for use as a filename
+	id = strings.ReplaceAll(id, " ", "-")
+	id = strings.ReplaceAll(id, "/", "-")
+	id = strings.ToLower(id)
+
+	filename := filepath.Join(conventionsPath, id+format.Extension())
+
+	// Check if a file with the same id already exists, in any format
+	var existing []string
+	for _, f := range conventions.Formats {
+		if name := filepath.Join(conventionsPath, id+f.Extension()); fileExists(name) {
+			existing = append(existing, name)
+		}
+	}
+	if source == "" {
+		source = filename
+	}
+	if err := validateConvention(source, data, append(existing, filename)...); err != nil {
+		return "", err
+	}
+	if len(existing) > 0 && !force {
+		return "", fmt.Errorf("convention '%s' already exists at %s\nuse a different --id, --force to replace it, or remove the existing file", id, existing[0])
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", err
+	}
+	// a replaced convention in another format would otherwise still be loaded
+	for _, name := range existing {
+		if name != filename {
+			if err := os.Remove(name); err != nil {
+				return "", err
+			}
+		}
+	}
+	return filename, nil
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 var listConventionCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Presents a list of known conventions.",
@@ -160,3 +366,284 @@ var listConventionCmd = &cobra.Command{
 		}
 	},
 }
+
+var showConventionCmd = &cobra.Command{
+	Use:   "show <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Shows the rules of a convention and where it is defined",
+	Long: `Shows the rules of the convention with the given name (case-insensitive), or
+custom convention id, and whether it is built-in or user-defined along with
+its source file. When a custom convention shares its name with another, each
+is shown; the last one takes precedence when extended.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, err := conventions.Load()
+		failOnError(err)
+
+		matches := matchConventions(*all, args[0])
+		if len(matches) == 0 {
+			failOnError(fmt.Errorf("convention '%s' not found", args[0]))
+		}
+
+		for i, c := range matches {
+			if i > 0 {
+				fmt.Println()
+			}
+			if c.BuiltIn() {
+				fmt.Println("Source: built-in")
+			} else {
+				fmt.Printf("Source: %s\n", c.Source)
+			}
+			failOnError(c.Print())
+		}
+	},
+}
+
+var removeConventionCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Removes a custom convention",
+	Long: `Removes the custom convention with the given name (case-insensitive), or
+custom convention id, from the conventions directory. Built-in conventions
+cannot be removed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := findCustomConvention(args[0])
+		failOnError(err)
+
+		failOnError(os.Remove(c.Source))
+		fmt.Printf("convention '%s' removed from %s\n", c.Name, c.Source)
+	},
+}
+
+var editConventionCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Opens a custom convention in your editor",
+	Long: `Opens the custom convention with the given name (case-insensitive), or
+custom convention id, in $VISUAL or $EDITOR (default vi). The edited
+convention is validated when the editor exits; when it is invalid, you can
+edit it again or discard the changes. The file is only replaced by a valid
+convention.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := findCustomConvention(args[0])
+		failOnError(err)
+
+		saved, err := editConvention(c.Source, bufio.NewReader(os.Stdin), os.Stdout)
+		failOnError(err)
+		if saved {
+			fmt.Printf("convention saved to %s\n", c.Source)
+		} else {
+			fmt.Println("changes discarded")
+		}
+	},
+}
+
+// matchConventions returns the conventions with the given name (case-insensitive) or custom convention id
+func matchConventions(all []model.Convention, name string) []model.Convention {
+	var matches []model.Convention
+	for _, c := range all {
+		id := strings.TrimSuffix(filepath.Base(c.Source), filepath.Ext(c.Source))
+		if strings.EqualFold(c.Name, name) || (!c.BuiltIn() && strings.EqualFold(id, name)) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// findCustomConvention returns the last custom convention with the given name or id, which takes precedence
+// over any other of the same name. Other conventions are not loaded, so that an invalid convention file can still
+// be found by its id, or by its name while it can be decoded.
+func findCustomConvention(name string) (*model.Convention, error) {
+	files, err := conventions.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *model.Convention
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		c := &model.Convention{Name: id}
+		if data, err := os.ReadFile(file); err == nil {
+			if decoded, err := conventions.Decode(file, data); err == nil && decoded.Name != "" {
+				c = decoded
+			}
+		}
+		c.Source = file
+		if strings.EqualFold(c.Name, name) || strings.EqualFold(id, name) {
+			found = c
+		}
+	}
+	if found != nil {
+		return found, nil
+	}
+
+	if matches := matchConventions(conventions.DefaultConventions, name); len(matches) > 0 {
+		return nil, fmt.Errorf("convention '%s' is built-in and cannot be changed: add a custom convention with the same name to replace it", matches[0].Name)
+	}
+	return nil, fmt.Errorf("convention '%s' not found", name)
+}
+
+// editConvention opens a copy of the convention file at path in the user's editor until it holds a valid
+// convention, which replaces the file, or the user discards the changes. It returns whether the file was replaced.
+func editConvention(path string, in *bufio.Reader, out io.Writer) (bool, error) {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	// the copy keeps the extension, so that the editor and the decoder know its format
+	tmp, err := os.CreateTemp("", "ossify-convention-*"+filepath.Ext(path))
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(original); err != nil {
+		_ = tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+
+	for {
+		if err := runEditor(tmp.Name()); err != nil {
+			return false, err
+		}
+
+		data, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(data, original) {
+			return false, nil
+		}
+
+		if err = validateConvention(path, data, path); err == nil {
+			return true, os.WriteFile(path, data, 0644)
+		}
+
+		_, _ = fmt.Fprintf(out, "%v\nedit again? [Y/n] ", err)
+		answer, readErr := in.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer == "n" || answer == "no" || (readErr != nil && answer == "") {
+			return false, nil
+		}
+	}
+}
+
+// runEditor opens path in $VISUAL or $EDITOR, which may include arguments, or in vi
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	args := strings.Fields(editor)
+	c := exec.Command(args[0], append(args[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("running editor %s: %w", editor, err)
+	}
+	return nil
+}
+
+var validateConventionCmd = &cobra.Command{
+	Use:   "validate [file...]",
+	Short: "Validates convention files",
+	Long: `Validates the given convention files, or every custom convention when no file
+is given. Each problem is reported with its file, line, column and JSON path:
+  our-go.json:4:16: $.rules[0].level: level mandatory is not valid: ...
+
+Besides malformed documents, validation reports unknown fields, unknown levels and
+types, empty values, duplicate rules, rules both requiring and prohibiting
+the same item, and conventions extending unknown conventions or forming a
+cycle. Invalid custom conventions are also reported whenever conventions are
+loaded, e.g. by "ossify check". Deprecated names, such as the type unspecified,
+are reported as warnings, which fail validation but do not prevent loading.
+
+Exit codes:
+  0 - All conventions are valid
+  1 - One or more conventions are invalid`,
+	Run: func(cmd *cobra.Command, args []string) {
+		failOnError(validateConventions(args, os.Stdout))
+	},
+}
+
+// validateConventions validates the convention files, or the custom conventions when files is empty, and
+// writes a confirmation to w when they are valid
+func validateConventions(files []string, w io.Writer) error {
+	known, err := conventions.Load()
+	if len(files) == 0 {
+		if err != nil {
+			return err
+		}
+		// loading ignores warnings, which are reported here
+		warnings, err := customWarnings()
+		if err != nil {
+			return err
+		}
+		if len(warnings) > 0 {
+			return &conventions.ValidationError{Diagnostics: warnings}
+		}
+		custom := 0
+		for _, c := range *known {
+			if !c.BuiltIn() {
+				custom++
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%d custom conventions are valid\n", custom)
+		return nil
+	}
+
+	var diagnostics []conventions.Diagnostic
+	var loaded []model.Convention
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if found := conventions.Validate(file, data); len(found) > 0 {
+			diagnostics = append(diagnostics, found...)
+			continue
+		}
+		convention, err := conventions.Decode(file, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		loaded = append(loaded, *convention)
+	}
+	if len(diagnostics) > 0 {
+		return &conventions.ValidationError{Diagnostics: diagnostics}
+	}
+
+	// inheritance is resolved against the known conventions, which must be valid themselves
+	if err != nil {
+		return err
+	}
+	if _, err := conventions.Resolve(append(*known, loaded...)); err != nil {
+		return err
+	}
+	for _, file := range files {
+		_, _ = fmt.Fprintf(w, "%s is valid\n", file)
+	}
+	return nil
+}
+
+// customWarnings returns the warnings of the custom convention files
+func customWarnings() ([]conventions.Diagnostic, error) {
+	files, err := conventions.Files()
+	if err != nil {
+		return nil, err
+	}
+	var warnings []conventions.Diagnostic
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, conventions.Validate(file, data)...)
+	}
+	return warnings, nil
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jimschubert/ossify/internal/config"
+	"github.com/jimschubert/ossify/internal/config/conventions"
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+func TestSaveConvention(t *testing.T) {
+	conventionPath := filepath.Join(t.TempDir(), "conventions")
+	originalManager := config.ConfigManager
+	config.ConfigManager = &config.Manager{
+		Load: func() (*config.Config, error) { return &config.Config{ConventionPath: conventionPath}, nil },
+		Save: func(c *config.Config) error { return nil },
+	}
+	defer func() { config.ConfigManager = originalManager }()
+
+	convention := model.Convention{Name: "Our Service", Rules: []model.Rule{
+		{Level: model.Required, Type: model.File, Value: "CODEOWNERS"},
+	}}
+
+	filename, err := saveConvention(convention, "", false)
+	if err != nil {
+		t.Fatalf("saveConvention() error = %v", err)
+	}
+	if want := filepath.Join(conventionPath, "our-service.json"); filename != want {
+		t.Errorf("saveConvention() = %s, want %s", filename, want)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read saved convention: %v", err)
+	}
+	var saved model.Convention
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Name != convention.Name || len(saved.Rules) != 1 {
+		t.Errorf("saved convention = %+v, %v", saved, err)
+	}
+
+	if _, err := saveConvention(convention, "", false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an error saving over an existing convention, got %v", err)
+	}
+	convention.Rules = append(convention.Rules, model.Rule{Level: model.Required, Type: model.File, Value: "SECURITY.md"})
+	if _, err := saveConvention(convention, "", true); err != nil {
+		t.Errorf("saveConvention() with force error = %v", err)
+	}
+	if data, _ := os.ReadFile(filename); !strings.Contains(string(data), "SECURITY.md") {
+		t.Errorf("forced save did not replace the convention:\n%s", data)
+	}
+	if _, err := saveConvention(model.Convention{Name: "Empty"}, "", false); err == nil {
+		t.Errorf("expected an error saving a convention without rules")
+	}
+	if _, err := saveConvention(model.Convention{Name: "Orphan", Extends: []string{"Cobol"}}, "", false); err == nil {
+		t.Errorf("expected an error saving a convention extending an unknown convention")
+	}
+}
+
+func TestFindCustomConvention(t *testing.T) {
+	conventionPath := t.TempDir()
+	originalManager := config.ConfigManager
+	config.ConfigManager = &config.Manager{
+		Load: func() (*config.Config, error) { return &config.Config{ConventionPath: conventionPath}, nil },
+		Save: func(c *config.Config) error { return nil },
+	}
+	defer func() { config.ConfigManager = originalManager }()
+
+	for name, content := range map[string]string{
+		"our-go.json": `{"name": "Go", "extends": ["Go"], "rules": [{"level": "required", "type": "file", "value": "CODEOWNERS"}]}`,
+		"tidy.json":   `{"name": "Tidy Repo", "rules": [{"level": "prohibited", "type": "file", "value": ".DS_Store"}]}`,
+		// invalid conventions, which prevent loading, can still be found to be edited or removed
+		"dup.json":    `{"name": "Dup", "rules": [{"level": "required", "type": "file", "value": "LICENSE"}, {"level": "required", "type": "file", "value": "LICENSE"}]}`,
+		"broken.yaml": "name: [",
+		".DS_Store":   "\x00\x00\x00\x01Bud1",
+	} {
+		if err := os.WriteFile(filepath.Join(conventionPath, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		search     string
+		wantSource string
+		wantErr    string
+	}{
+		{name: "custom convention shadowing a built-in", search: "go", wantSource: "our-go.json"},
+		{name: "by id", search: "tidy", wantSource: "tidy.json"},
+		{name: "by name", search: "Tidy Repo", wantSource: "tidy.json"},
+		{name: "invalid convention by name", search: "dup", wantSource: "dup.json"},
+		{name: "undecodable convention by id", search: "broken", wantSource: "broken.yaml"},
+		{name: "built-in", search: "Rust", wantErr: "is built-in"},
+		{name: "unknown", search: "Cobol", wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findCustomConvention(tt.search)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("findCustomConvention() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findCustomConvention() error = %v", err)
+			}
+			if want := filepath.Join(conventionPath, tt.wantSource); got.Source != want {
+				t.Errorf("findCustomConvention() source = %s, want %s", got.Source, want)
+			}
+		})
+	}
+}
+
+func TestEditConvention(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tidy.json")
+	original := `{"name": "Tidy", "rules": [{"level": "prohibited", "type": "file", "value": ".DS_Store"}]}`
+
+	// the editor copies the next of the numbered edits over the file it is given
+	editor := filepath.Join(dir, "editor.sh")
+	script := `#!/bin/sh
+n=$(cat "` + dir + `/count" 2>/dev/null || echo 0)
+n=$((n + 1))
+echo $n > "` + dir + `/count"
+cp "` + dir + `/edit$n.json" "$1"
+`
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write editor: %v", err)
+	}
+	t.Setenv("VISUAL", editor)
+
+	tests := []struct {
+		name      string
+		edits     []string
+		answers   string
+		wantSaved bool
+		want      string
+	}{
+		{
+			name:      "saves a valid edit",
+			edits:     []string{`{"name": "Tidy", "rules": [{"level": "prohibited", "type": "directory", "value": ".idea"}]}`},
+			wantSaved: true,
+			want:      ".idea",
+		},
+		{
+			name: "edits again after an invalid edit",
+			edits: []string{
+				`{"name": "Tidy", "rules": [{"level": "forbidden", "type": "directory", "value": ".idea"}]}`,
+				`{"name": "Tidy", "rules": [{"level": "prohibited", "type": "directory", "value": ".vscode"}]}`,
+			},
+			answers:   "y\n",
+			wantSaved: true,
+			want:      ".vscode",
+		},
+		{
+			name:    "discards an invalid edit",
+			edits:   []string{`{"name": "", "rules": []}`},
+			answers: "n\n",
+			want:    ".DS_Store",
+		},
+		{
+			name:  "leaves an unchanged file",
+			edits: []string{original},
+			want:  ".DS_Store",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Remove(filepath.Join(dir, "count"))
+			for i, edit := range tt.edits {
+				if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("edit%d.json", i+1)), []byte(edit), 0644); err != nil {
+					t.Fatalf("failed to write edit: %v", err)
+				}
+			}
+			if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+				t.Fatalf("failed to write convention: %v", err)
+			}
+
+			var out bytes.Buffer
+			saved, err := editConvention(path, bufio.NewReader(strings.NewReader(tt.answers)), &out)
+			if err != nil {
+				t.Fatalf("editConvention() error = %v", err)
+			}
+			if saved != tt.wantSaved {
+				t.Errorf("editConvention() saved = %v, want %v\n%s", saved, tt.wantSaved, out.String())
+			}
+			if data, _ := os.ReadFile(path); !strings.Contains(string(data), tt.want) {
+				t.Errorf("convention = %s, want it to contain %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConventions(t *testing.T) {
+	conventionPath := filepath.Join(t.TempDir(), "conventions")
+	originalManager := config.ConfigManager
+	config.ConfigManager = &config.Manager{
+		Load: func() (*config.Config, error) { return &config.Config{ConventionPath: conventionPath}, nil },
+		Save: func(c *config.Config) error { return nil },
+	}
+	defer func() { config.ConfigManager = originalManager }()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name        string
+		content     string
+		errContains string
+	}{
+		{"valid", `{"name": "Ours", "rules": [{"level": "required", "type": "file", "value": "README.md"}]}`, ""},
+		{"extends built-in", `{"name": "Ours", "extends": ["Go"]}`, ""},
+		{"unknown level", "{\n  \"name\": \"Ours\",\n  \"rules\": [{\"level\": \"mandatory\", \"type\": \"file\", \"value\": \"a\"}]\n}", ":3:23: $.rules[0].level: level mandatory is not valid"},
+		{"unknown parent", `{"name": "Ours", "extends": ["Cobol"]}`, "extends unknown convention 'Cobol'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := write(strings.ReplaceAll(tt.name, " ", "-")+".json", tt.content)
+			var out bytes.Buffer
+			err := validateConventions([]string{path}, &out)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("validateConventions() error = %v", err)
+				}
+				if !strings.Contains(out.String(), path+" is valid") {
+					t.Errorf("validateConventions() output = %q", out.String())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("validateConventions() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+
+	var out bytes.Buffer
+	if err := validateConventions(nil, &out); err != nil || out.String() != "0 custom conventions are valid\n" {
+		t.Errorf("validateConventions() = %q, %v", out.String(), err)
+	}
+
+	// custom conventions with warnings load, but are reported
+	if err := os.MkdirAll(conventionPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := filepath.Join(conventionPath, "legacy.json")
+	if err := os.WriteFile(legacy, []byte(`{"name": "Legacy", "rules": [{"level": "prohibited", "type": "unspecified", "value": ".bak"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conventions.Load(); err != nil {
+		t.Errorf("Load() error = %v", err)
+	}
+	if err := validateConventions(nil, &out); err == nil || !strings.Contains(err.Error(), "warning: type unspecified is deprecated") {
+		t.Errorf("validateConventions() error = %v, want a warning", err)
+	}
+}
+
+func TestSaveConventionDocument(t *testing.T) {
+	conventionPath := filepath.Join(t.TempDir(), "conventions")
+	originalManager := config.ConfigManager
+	config.ConfigManager = &config.Manager{
+		Load: func() (*config.Config, error) { return &config.Config{ConventionPath: conventionPath}, nil },
+		Save: func(c *config.Config) error { return nil },
+	}
+	defer func() { config.ConfigManager = originalManager }()
+
+	convention := model.Convention{Name: "Ours", Rules: []model.Rule{
+		{Level: model.Required, Type: model.File, Value: "CODEOWNERS"},
+	}}
+	jsonFile, err := saveConvention(convention, "", false)
+	if err != nil {
+		t.Fatalf("saveConvention() error = %v", err)
+	}
+
+	data := []byte("# owned by the platform team\nname: Ours\nrules:\n  - { level: required, type: file, value: CODEOWNERS }\n")
+	if _, err := saveConventionDocument(convention, data, conventions.YAML, "ours.yaml", "", false); err == nil || !strings.Contains(err.Error(), jsonFile) {
+		t.Errorf("expected an error saving over the JSON convention, got %v", err)
+	}
+
+	filename, err := saveConventionDocument(convention, data, conventions.YAML, "ours.yaml", "", true)
+	if err != nil {
+		t.Fatalf("saveConventionDocument() error = %v", err)
+	}
+	if want := filepath.Join(conventionPath, "ours.yaml"); filename != want {
+		t.Errorf("saveConventionDocument() = %s, want %s", filename, want)
+	}
+	if saved, _ := os.ReadFile(filename); !bytes.Equal(saved, data) {
+		t.Errorf("saved document = %q, want the original with its comments", saved)
+	}
+	if _, err := os.Stat(jsonFile); !os.IsNotExist(err) {
+		t.Errorf("expected the replaced JSON convention to be removed, got %v", err)
+	}
+
+	all, err := conventions.Load()
+	if err != nil {
+		t.Fatalf("conventions.Load() error = %v", err)
+	}
+	if c := findConventionByName(*all, "Ours"); c == nil || c.Source != filename {
+		t.Errorf("loaded convention = %+v, want it loaded from %s", c, filename)
+	}
+
+	// documents which would fail to load are not saved
+	conflicting := []byte(`{"name": "Dup", "rules": [
+  {"level": "required", "type": "file", "value": "LICENSE"},
+  {"level": "prohibited", "type": "file", "value": "LICENSE"}
+]}`)
+	dup := model.Convention{Name: "Dup"}
+	if _, err := saveConventionDocument(dup, conflicting, conventions.JSON, "dup.json", "", false); err == nil || !strings.Contains(err.Error(), "dup.json:3:3: $.rules[1]: prohibited LICENSE conflicts with required rule $.rules[0]") {
+		t.Errorf("expected a conflict saving dup.json, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(conventionPath, "dup.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the invalid convention not to be saved, got %v", err)
+	}
+}
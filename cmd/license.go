@@ -2,12 +2,17 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path"
+	"path/filepath"
+	"time"
 
 	"github.com/jimschubert/ossify/internal/config"
 	"github.com/jimschubert/ossify/internal/licenses"
+	"github.com/jimschubert/ossify/internal/model"
+	"github.com/jimschubert/ossify/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +24,15 @@ type LicenseFlags struct {
 	keyword         []string
 	search          string
 	details         bool
+	holder          string
+	year            string
+	project         string
+	description     string
+	organization    string
+	url             string
+	email           string
+	strict          bool
+	top             int
 }
 
 func init() {
@@ -28,6 +42,7 @@ func init() {
 	// license
 	licenseCmd.AddCommand(listLicenseCmd)
 	licenseCmd.AddCommand(addLicenseCmd)
+	licenseCmd.AddCommand(detectLicenseCmd)
 
 	licenseCmd.Flags().StringVarP(&licenseFlags.licenseId, "id", "i", "",
 		"Get details about a single license by ID.")
@@ -41,6 +56,24 @@ func init() {
 	licenseCmd.Flags().BoolVar(&licenseFlags.details, "details", false,
 		"When included with the id option, prints only the details of the requested license rather than the license text.")
 
+	// license text substitution
+	licenseCmd.Flags().StringVar(&licenseFlags.holder, "holder", "",
+		"Copyright holder substituted into the license text (defaults to config, then git user.name).")
+	licenseCmd.Flags().StringVar(&licenseFlags.year, "year", "",
+		"Copyright year or range substituted into the license text, e.g. 2024, 2019-2024, or 2019- (defaults to the current year).")
+	licenseCmd.Flags().StringVar(&licenseFlags.project, "project", "",
+		"Project name substituted into the license text (defaults to the git repository directory name).")
+	licenseCmd.Flags().StringVar(&licenseFlags.description, "description", "",
+		"One-line project description, used by licenses which ask for the program's name and purpose.")
+	licenseCmd.Flags().StringVar(&licenseFlags.organization, "organization", "",
+		"Organization substituted into the license text (defaults to config, then the holder).")
+	licenseCmd.Flags().StringVar(&licenseFlags.url, "url", "",
+		"Project or holder URL substituted into the license text (defaults to config).")
+	licenseCmd.Flags().StringVar(&licenseFlags.email, "email", "",
+		"Contact email substituted into the license text (defaults to config, then git user.email).")
+	licenseCmd.Flags().BoolVar(&licenseFlags.strict, "strict", false,
+		"Fail if any placeholder in the license text is left unfilled.")
+
 	// license add
 	addLicenseCmd.Flags().StringVarP(&licenseFlags.licenseId, "id", "i", "",
 		"The identifier to be associated with your customized license. This will take "+
@@ -50,6 +83,10 @@ func init() {
 		"The template to add for the given identifier.")
 
 	// license list
+
+	// license detect
+	detectLicenseCmd.Flags().IntVar(&licenseFlags.top, "top", 3,
+		"The number of runner-up licenses to display after the best match.")
 }
 
 var licenseCmd = &cobra.Command{
@@ -85,8 +122,11 @@ var licenseCmd = &cobra.Command{
 				if details {
 					_ = license.PrintDetails()
 				} else {
-					err := licenses.PrintLicenseText(license.Id, conf.LicensePath)
+					values, err := licenseValues(licenseFlags, conf)
 					failOnError(err)
+					text, err := licenses.RenderLicenseText(license.Id, conf.LicensePath, *values, licenseFlags.strict)
+					failOnError(err)
+					fmt.Println(text)
 				}
 			}
 		} else if len(keywords) > 0 {
@@ -165,3 +205,144 @@ var listLicenseCmd = &cobra.Command{
 		}
 	},
 }
+
+// licenseValues resolves the values substituted into license text. Flags take precedence over
+// configured defaults, which take precedence over git configuration.
+func licenseValues(flags *LicenseFlags, conf *config.Config) (*licenses.Values, error) {
+	year, err := licenses.ParseYear(flags.year, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	values := &licenses.Values{
+		Holder:       firstNonEmpty(flags.holder, conf.LicenseDefaults.Holder, util.GitConfig("user.name")),
+		Year:         year,
+		Project:      flags.project,
+		Description:  flags.description,
+		Organization: firstNonEmpty(flags.organization, conf.LicenseDefaults.Organization),
+		URL:          firstNonEmpty(flags.url, conf.LicenseDefaults.URL),
+		Email:        firstNonEmpty(flags.email, conf.LicenseDefaults.Email, util.GitConfig("user.email")),
+	}
+
+	if values.Project == "" {
+		if wd, err := os.Getwd(); err == nil {
+			if topLevel := util.GitTopLevel(wd); topLevel != "" {
+				values.Project = filepath.Base(topLevel)
+			}
+		}
+	}
+
+	return values, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// minimumDetectConfidence is the confidence below which detect reports that no license was identified.
+const minimumDetectConfidence = 0.5
+
+// licenseFileNames are the files detect looks for when given a directory.
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING", "COPYING.md", "COPYING.txt"}
+
+var detectLicenseCmd = &cobra.Command{
+	Use:   "detect [path]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Identifies the license of an existing license file.",
+	Long: `Identifies the license of an existing license file.
+
+The file is normalized (whitespace, punctuation, placeholders and copyright lines are ignored)
+and compared against the built-in license texts and your custom license templates.
+The best matching license is reported with a confidence score, followed by the closest runner-ups.
+
+The path defaults to the current directory. When a directory is given, the first of
+LICENSE, LICENSE.md, LICENSE.txt, LICENCE or COPYING found in it is used.
+
+Example:
+  ossify license detect
+  ossify license detect vendor/github.com/spf13/cobra/LICENSE.txt --top 5`,
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, err := config.ConfigManager.Load()
+		failOnError(err)
+
+		target := "."
+		if len(args) == 1 {
+			target = args[0]
+		}
+
+		licenseFile, err := resolveLicenseFile(target)
+		if err != nil {
+			cobra.CheckErr(fmt.Errorf("locating license file: %w", err))
+		}
+
+		content, err := os.ReadFile(licenseFile)
+		if err != nil {
+			cobra.CheckErr(fmt.Errorf("reading license file: %w", err))
+		}
+
+		matches, err := licenses.Detect(string(content), conf.LicensePath)
+		if err != nil {
+			cobra.CheckErr(fmt.Errorf("detecting license: %w", err))
+		}
+
+		allLicenses, err := licenses.Load()
+		failOnError(err)
+
+		identified := len(matches) > 0 && matches[0].Confidence >= minimumDetectConfidence
+		if !identified {
+			fmt.Printf("%s: no license matched with at least %.0f%% confidence\n", licenseFile, minimumDetectConfidence*100)
+		} else {
+			fmt.Printf("%s: %s\n", licenseFile, describeMatch(matches[0], allLicenses))
+		}
+
+		top := min(licenseFlags.top, len(matches)-1)
+		if top > 0 {
+			fmt.Println("\nRunner-ups:")
+			for _, match := range matches[1 : top+1] {
+				fmt.Printf("  %s\n", describeMatch(match, allLicenses))
+			}
+		}
+
+		if !identified {
+			os.Exit(1)
+		}
+	},
+}
+
+// resolveLicenseFile returns target if it is a file, or the first known license file within target if it is a directory.
+func resolveLicenseFile(target string) (string, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return target, nil
+	}
+
+	for _, name := range licenseFileNames {
+		candidate := filepath.Join(target, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no license file found in %s", target)
+}
+
+func describeMatch(match licenses.Match, allLicenses *model.Licenses) string {
+	description := fmt.Sprintf("%-20s %5.1f%%", match.ID, match.Confidence*100)
+	if match.Custom {
+		return description + " (custom template)"
+	}
+	for _, license := range *allLicenses {
+		if license.Id == match.ID {
+			return fmt.Sprintf("%s (%s)", description, license.Name)
+		}
+	}
+	return description
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jimschubert/ossify/internal/config"
+	"github.com/jimschubert/ossify/internal/config/conventions"
+	"github.com/jimschubert/ossify/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// schemaDocuments are the documents whose schema is published, by the name given to "ossify schema"
+var schemaDocuments = map[string]*schema.Schema{
+	"convention": conventions.ConventionSchema,
+	"config":     schema.Document(config.Config{}, "ossify settings"),
+}
+
+var schemaCmd = &cobra.Command{
+	Use:       "schema <convention|config>",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"convention", "config"},
+	Short:     "Prints the JSON Schema of convention or config documents",
+	Long: `Prints the JSON Schema of convention documents or of the settings file
+(~/.config/ossify/settings.json), for completion and validation in editors:
+  ossify schema convention > ossify-convention.schema.json
+
+Reference the schema from a JSON convention with "$schema", or from YAML with a
+yaml-language-server comment:
+  # yaml-language-server: $schema=./ossify-convention.schema.json
+
+The same schema is checked when conventions are validated or loaded with
+"ossify check --file".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		failOnError(writeSchema(os.Stdout, args[0]))
+	},
+}
+
+// writeSchema writes the indented JSON Schema of the named document to w
+func writeSchema(w io.Writer, name string) error {
+	document, ok := schemaDocuments[name]
+	if !ok {
+		return fmt.Errorf("unknown schema '%s': expected convention or config", name)
+	}
+	output, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
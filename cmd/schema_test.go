@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestWriteSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties []string
+	}{
+		{"convention", []string{"$schema", "detect", "extends", "name", "remove", "rules"}},
+		{"config", []string{"$schema", "conventionPath", "licenseDefaults", "licensePath"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeSchema(&out, tt.name); err != nil {
+				t.Fatalf("writeSchema() error = %v", err)
+			}
+
+			var document struct {
+				Schema     string                     `json:"$schema"`
+				Properties map[string]json.RawMessage `json:"properties"`
+			}
+			if err := json.Unmarshal(out.Bytes(), &document); err != nil {
+				t.Fatalf("schema is not JSON: %v\n%s", err, out.String())
+			}
+			if document.Schema == "" {
+				t.Errorf("schema does not name its dialect")
+			}
+			var properties []string
+			for name := range document.Properties {
+				properties = append(properties, name)
+			}
+			slices.Sort(properties)
+			if !slices.Equal(properties, tt.properties) {
+				t.Errorf("properties = %v, want %v", properties, tt.properties)
+			}
+		})
+	}
+
+	if err := writeSchema(&bytes.Buffer{}, "baseline"); err == nil {
+		t.Errorf("expected an error for an unknown schema")
+	}
+}
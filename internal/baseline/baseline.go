@@ -0,0 +1,179 @@
+// Package baseline records the known failures of a convention check so that later checks only fail on new
+// violations, letting legacy projects adopt conventions and ratchet toward compliance.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+// Version is the version of the baseline file format
+const Version = 1
+
+// DefaultFile is the conventional name of a baseline file, at the root of the checked directory
+const DefaultFile = ".ossify-baseline.json"
+
+// Entry is a recorded failure
+type Entry struct {
+	Convention string `json:"convention"`
+	// Module is the module of a recursive check the failure was recorded in
+	Module   string `json:"module,omitempty"`
+	Level    string `json:"level"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	Contains string `json:"contains,omitempty"`
+	Matches  string `json:"matches,omitempty"`
+	Each     string `json:"each,omitempty"`
+	// Alternatives are accepted in place of Value
+	Alternatives []string `json:"alternatives,omitempty"`
+	// When is the condition under which the rule applies, e.g. "file Dockerfile exists"
+	When string `json:"when,omitempty"`
+	// Paths are the offending paths of a pattern rule or a rule with alternatives; a failure with any other path
+	// is a new violation
+	Paths []string `json:"paths,omitempty"`
+}
+
+// Baseline is the set of failures known when it was written
+type Baseline struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// New records the failures and warnings of results. Suppressed failures are not recorded, since they are
+// already waived.
+func New(results []*model.CheckResult) *Baseline {
+	b := &Baseline{Version: Version, Entries: make([]Entry, 0)}
+	for _, result := range results {
+		for _, r := range result.Results {
+			if status := r.Status(); status != model.StatusFail && status != model.StatusWarn {
+				continue
+			}
+			entry := newEntry(result, r.Rule)
+			entry.Paths = r.Paths
+			b.Entries = append(b.Entries, entry)
+		}
+	}
+	return b
+}
+
+func newEntry(result *model.CheckResult, rule model.Rule) Entry {
+	entry := Entry{
+		Convention:   result.Convention,
+		Module:       result.Module,
+		Level:        rule.Level.String(),
+		Type:         rule.Type.String(),
+		Value:        rule.Value,
+		Contains:     rule.Contains,
+		Matches:      rule.Matches,
+		Each:         rule.Each,
+		Alternatives: rule.Alternatives,
+	}
+	if rule.When != nil {
+		entry.When = rule.When.String()
+	}
+	return entry
+}
+
+// Load reads a baseline file
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %w", path, err)
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s: expected %d", b.Version, path, Version)
+	}
+	return &b, nil
+}
+
+// Write saves the baseline to path
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Apply marks the failures of results recorded in the baseline as suppressed, with source naming the baseline,
+// so that only new violations fail the check. A rule whose level changed, or a pattern rule matching a path not
+// recorded, is a new violation. It returns the entries of checked conventions which no longer fail.
+func (b *Baseline) Apply(results []*model.CheckResult, source string) []Entry {
+	matched := make([]bool, len(b.Entries))
+	checked := make(map[string]bool)
+
+	for _, result := range results {
+		checked[checkedKey(result.Module, result.Convention)] = true
+		for i := range result.Results {
+			r := &result.Results[i]
+			if r.Passed || r.Skipped {
+				continue
+			}
+
+			key := newEntry(result, r.Rule)
+			for j, entry := range b.Entries {
+				if !sameRule(entry, key) {
+					continue
+				}
+				// the rule still fails, even if it has new paths
+				matched[j] = true
+				if r.Suppression == nil && subset(r.Paths, entry.Paths) {
+					r.Suppression = &model.Suppression{
+						Convention: result.Convention,
+						Value:      r.Rule.Value,
+						Reason:     "recorded in baseline",
+						Source:     source,
+					}
+				}
+			}
+		}
+		result.Recount()
+	}
+
+	var fixed []Entry
+	for i, entry := range b.Entries {
+		if !matched[i] && checked[checkedKey(entry.Module, entry.Convention)] {
+			fixed = append(fixed, entry)
+		}
+	}
+	return fixed
+}
+
+// String describes the entry as "convention: level type value", qualifying the convention by its module
+func (e Entry) String() string {
+	if e.Module != "" {
+		return fmt.Sprintf("%s (%s): %s %s %s", e.Convention, e.Module, e.Level, e.Type, e.Value)
+	}
+	return fmt.Sprintf("%s: %s %s %s", e.Convention, e.Level, e.Type, e.Value)
+}
+
+func checkedKey(module, convention string) string {
+	return module + "\x00" + strings.ToLower(convention)
+}
+
+func sameRule(a, b Entry) bool {
+	return strings.EqualFold(a.Convention, b.Convention) && a.Module == b.Module &&
+		a.Level == b.Level && a.Type == b.Type && a.Value == b.Value &&
+		a.Contains == b.Contains && a.Matches == b.Matches && a.Each == b.Each &&
+		slices.Equal(a.Alternatives, b.Alternatives) && a.When == b.When
+}
+
+// subset reports whether every path is one of known
+func subset(paths, known []string) bool {
+	for _, p := range paths {
+		if !slices.Contains(known, p) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+func checkResult(convention string, results ...model.RuleResult) *model.CheckResult {
+	result := &model.CheckResult{Convention: convention, Results: results}
+	result.Recount()
+	return result
+}
+
+func TestBaseline_Apply(t *testing.T) {
+	recorded := []*model.CheckResult{
+		checkResult("Go",
+			model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "LICENSE"}, Message: "missing"},
+			model.RuleResult{Rule: model.Rule{Level: model.Preferred, Type: model.Directory, Value: "docs"}, Message: "missing"},
+			model.RuleResult{Rule: model.Rule{Level: model.Prohibited, Type: model.Pattern, Value: "**/*.orig"}, Paths: []string{"a.orig"}, Message: "found"},
+			model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "README.md"}, Passed: true, Message: "found"},
+		),
+		checkResult("Node.js",
+			model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "package.json"}, Message: "missing"},
+		),
+	}
+
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := New(recorded).Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	b, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(b.Entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d: %v", len(b.Entries), b.Entries)
+	}
+
+	// LICENSE is fixed, docs still fails, the pattern matches a new path, and CODEOWNERS is a new violation
+	current := []*model.CheckResult{
+		checkResult("go",
+			model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "LICENSE"}, Passed: true, Message: "found"},
+			model.RuleResult{Rule: model.Rule{Level: model.Preferred, Type: model.Directory, Value: "docs"}, Message: "missing"},
+			model.RuleResult{Rule: model.Rule{Level: model.Prohibited, Type: model.Pattern, Value: "**/*.orig"}, Paths: []string{"a.orig", "b.orig"}, Message: "found"},
+			model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "CODEOWNERS"}, Message: "missing"},
+		),
+	}
+
+	fixed := b.Apply(current, path)
+
+	want := []model.Status{model.StatusPass, model.StatusSuppressed, model.StatusFail, model.StatusFail}
+	for i, r := range current[0].Results {
+		if got := r.Status(); got != want[i] {
+			t.Errorf("result %s status = %s, want %s", r.Rule.Value, got, want[i])
+		}
+	}
+	if s := current[0].Results[1].Suppression; s == nil || s.Source != path {
+		t.Errorf("expected suppression from %s, got %+v", path, s)
+	}
+	if current[0].SuppressedCount != 1 || current[0].FailCount != 2 {
+		t.Errorf("counts = %d suppressed, %d failed", current[0].SuppressedCount, current[0].FailCount)
+	}
+
+	// the Node.js entry is not reported as fixed since that convention was not checked
+	if len(fixed) != 1 || fixed[0].Value != "LICENSE" {
+		t.Errorf("fixed = %v, want only LICENSE", fixed)
+	}
+}
+
+func TestBaseline_Apply_DistinguishesConditions(t *testing.T) {
+	conditional := model.Rule{Level: model.Required, Type: model.File, Value: "Dockerfile",
+		When: &model.Condition{Type: model.File, Value: "compose.yaml"}}
+	unconditional := model.Rule{Level: model.Required, Type: model.File, Value: "Dockerfile"}
+
+	b := New([]*model.CheckResult{checkResult("Go", model.RuleResult{Rule: conditional, Message: "missing"})})
+	if len(b.Entries) != 1 || b.Entries[0].When != "file compose.yaml exists" {
+		t.Fatalf("entries = %+v, want the condition recorded", b.Entries)
+	}
+
+	current := []*model.CheckResult{checkResult("Go",
+		model.RuleResult{Rule: conditional, Message: "missing"},
+		model.RuleResult{Rule: unconditional, Message: "missing"},
+	)}
+	b.Apply(current, DefaultFile)
+	if got := current[0].Results[0].Status(); got != model.StatusSuppressed {
+		t.Errorf("conditional rule status = %s, want %s", got, model.StatusSuppressed)
+	}
+	if got := current[0].Results[1].Status(); got != model.StatusFail {
+		t.Errorf("unconditional rule status = %s, want %s", got, model.StatusFail)
+	}
+}
+
+func TestLoad_UnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := (&Baseline{Version: Version + 1}).Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Errorf("expected an error for an unsupported version")
+	}
+}
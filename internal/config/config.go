@@ -8,8 +8,17 @@ import (
 )
 
 type Config struct {
-	LicensePath    string `json:"licensePath"`
-	ConventionPath string `json:"conventionPath"`
+	LicensePath     string          `json:"licensePath"`
+	ConventionPath  string          `json:"conventionPath"`
+	LicenseDefaults LicenseDefaults `json:"licenseDefaults,omitzero"`
+}
+
+// LicenseDefaults holds default values substituted into license templates when no flag is provided.
+type LicenseDefaults struct {
+	Holder       string `json:"holder,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	URL          string `json:"url,omitempty"`
+	Email        string `json:"email,omitempty"`
 }
 
 var defaultConfig Config
@@ -1,16 +1,74 @@
 package conventions
 
 import (
-	"encoding/json"
 	"errors"
 	"os"
 	"path"
+	"slices"
+	"strings"
 
 	"github.com/jimschubert/ossify/internal/config"
 	"github.com/jimschubert/ossify/internal/model"
 )
 
+// Load returns the built-in conventions followed by the custom conventions of the conventions directory, with
+// their extends resolved. Every invalid custom convention is reported in a *ValidationError.
 func Load() (*[]model.Convention, error) {
+	return LoadExcept()
+}
+
+// LoadExcept loads like Load, leaving out the custom convention files in skip, e.g. a file which is being replaced
+func LoadExcept(skip ...string) (*[]model.Convention, error) {
+	files, err := Files()
+	if err != nil {
+		return nil, err
+	}
+
+	var conventions = make([]model.Convention, len(DefaultConventions))
+	copy(conventions, DefaultConventions)
+
+	// every invalid file is reported, rather than only the first
+	var diagnostics []Diagnostic
+	for _, source := range files {
+		if slices.Contains(skip, source) {
+			continue
+		}
+		bytes, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+
+		if found := Errors(Validate(source, bytes)); len(found) > 0 {
+			diagnostics = append(diagnostics, found...)
+			continue
+		}
+
+		convention, err := Decode(source, bytes)
+		if err != nil {
+			diagnostics = append(diagnostics, Diagnostic{File: source, Path: "$", Line: 1, Column: 1, Message: err.Error()})
+			continue
+		}
+		convention.Source = source
+		conventions = append(conventions, *convention)
+	}
+
+	if len(diagnostics) > 0 {
+		return nil, &ValidationError{Diagnostics: diagnostics}
+	}
+
+	resolved, err := Resolve(conventions)
+	if err != nil {
+		return nil, err
+	}
+
+	// returns all available conventions and last known error
+	return &resolved, nil
+}
+
+// Files returns the paths of the custom convention documents in the conventions directory: the files with a
+// .json, .yaml, .yml or .toml extension, or with no extension, whose format is detected from their content. Other
+// files, such as .DS_Store, are ignored.
+func Files() ([]string, error) {
 	c, e := config.ConfigManager.Load()
 	if e != nil {
 		return nil, e
@@ -20,43 +78,36 @@ func Load() (*[]model.Convention, error) {
 		return nil, errors.New("invalid convention path")
 	}
 
-	var conventions = make([]model.Convention, 2)
-	copy(conventions, DefaultConventions)
+	if _, err := os.Stat(conventionPath); err != nil {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(conventionPath)
+	if err != nil {
+		return nil, err
+	}
 
-	if _, err := os.Stat(conventionPath); err == nil {
-		var files, err = os.ReadDir(conventionPath)
-		if err != nil {
-			return nil, err
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
 		}
-
-		for _, file := range files {
-			if !file.IsDir() {
-				var convention model.Convention
-
-				var bytes []byte
-				bytes, err = os.ReadFile(path.Join(conventionPath, file.Name()))
-				if err != nil {
-					// TODO: warn
-					continue
-				}
-
-				err = json.Unmarshal(bytes, &convention)
-				if err != nil {
-					// TODO: warn
-					continue
-				}
-				conventions = append(conventions, convention)
-			}
+		// files without an extension were written by earlier versions, as JSON
+		switch strings.ToLower(path.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml", ".toml", "":
+			files = append(files, path.Join(conventionPath, entry.Name()))
 		}
 	}
-
-	// returns all available conventions and last known error
-	return &conventions, nil
+	return files, nil
 }
 
 var DefaultConventions = []model.Convention{
 	StandardDistributionConvention,
 	GoConvention,
+	NodeConvention,
+	PythonConvention,
+	RustConvention,
+	MavenConvention,
+	GradleConvention,
 }
 
 var StandardDistributionConvention = model.Convention{
@@ -74,7 +125,8 @@ var StandardDistributionConvention = model.Convention{
 }
 
 var GoConvention = model.Convention{
-	Name: "Go",
+	Name:   "Go",
+	Detect: []string{"go.mod"},
 	Rules: []model.Rule{
 		{Level: model.Optional, Type: model.Directory, Value: "configs"},
 		{Level: model.Optional, Type: model.Directory, Value: "init"},
@@ -91,6 +143,83 @@ var GoConvention = model.Convention{
 	},
 }
 
+var NodeConvention = model.Convention{
+	Name:   "Node.js",
+	Detect: []string{"package.json"},
+	Rules: []model.Rule{
+		{Level: model.Required, Type: model.File, Value: "package.json"},
+		{Level: model.Required, Type: model.Pattern, Value: "{package-lock.json,npm-shrinkwrap.json,yarn.lock,pnpm-lock.yaml,bun.lock,bun.lockb}"},
+		{Level: model.Prohibited, Type: model.Pattern, Value: "node_modules/"},
+		{Level: model.Preferred, Type: model.File, Value: ".gitignore"},
+		{Level: model.Optional, Type: model.File, Value: ".nvmrc"},
+		{Level: model.Optional, Type: model.Directory, Value: "test"},
+		{Level: model.Required, Type: model.File, Value: "LICENSE"},
+		{Level: model.Required, Type: model.File, Value: "README.md"},
+	},
+}
+
+var PythonConvention = model.Convention{
+	Name:   "Python",
+	Detect: []string{"pyproject.toml", "setup.py", "setup.cfg"},
+	Rules: []model.Rule{
+		{Level: model.Required, Type: model.File, Value: "pyproject.toml"},
+		{Level: model.Preferred, Type: model.Directory, Value: "src"},
+		{Level: model.Required, Type: model.Directory, Value: "tests"},
+		{Level: model.Optional, Type: model.File, Value: "setup.py"},
+		{Level: model.Prohibited, Type: model.Pattern, Value: "**/__pycache__/"},
+		{Level: model.Prohibited, Type: model.Pattern, Value: "*.egg-info/"},
+		{Level: model.Required, Type: model.File, Value: "LICENSE"},
+		{Level: model.Required, Type: model.File, Value: "README.md"},
+	},
+}
+
+var RustConvention = model.Convention{
+	Name:   "Rust",
+	Detect: []string{"Cargo.toml"},
+	Rules: []model.Rule{
+		{Level: model.Required, Type: model.File, Value: "Cargo.toml"},
+		{Level: model.Required, Type: model.Pattern, Value: "src/{lib,main}.rs"},
+		{Level: model.Prohibited, Type: model.Pattern, Value: "target/"},
+		{Level: model.Optional, Type: model.Directory, Value: "tests"},
+		{Level: model.Optional, Type: model.Directory, Value: "examples"},
+		{Level: model.Optional, Type: model.Directory, Value: "benches"},
+		// Rust projects are commonly dual-licensed as LICENSE-MIT and LICENSE-APACHE
+		{Level: model.Required, Type: model.Pattern, Value: "LICENSE*"},
+		{Level: model.Required, Type: model.File, Value: "README.md"},
+	},
+}
+
+var MavenConvention = model.Convention{
+	Name:   "Java (Maven)",
+	Detect: []string{"pom.xml"},
+	Rules: []model.Rule{
+		{Level: model.Required, Type: model.File, Value: "pom.xml"},
+		{Level: model.Required, Type: model.Directory, Value: "src/main/java"},
+		{Level: model.Preferred, Type: model.Directory, Value: "src/test/java"},
+		{Level: model.Optional, Type: model.Directory, Value: "src/main/resources"},
+		{Level: model.Optional, Type: model.File, Value: "mvnw"},
+		{Level: model.Prohibited, Type: model.Pattern, Value: "target/"},
+		{Level: model.Required, Type: model.File, Value: "LICENSE"},
+		{Level: model.Required, Type: model.File, Value: "README.md"},
+	},
+}
+
+var GradleConvention = model.Convention{
+	Name:   "Java (Gradle)",
+	Detect: []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"},
+	Rules: []model.Rule{
+		{Level: model.Required, Type: model.Pattern, Value: "{build,settings}.gradle{,.kts}"},
+		{Level: model.Preferred, Type: model.File, Value: "gradlew"},
+		{Level: model.Preferred, Type: model.Directory, Value: "gradle/wrapper"},
+		{Level: model.Required, Type: model.Pattern, Value: "src/main/{java,kotlin}/"},
+		{Level: model.Preferred, Type: model.Pattern, Value: "src/test/{java,kotlin}/"},
+		{Level: model.Prohibited, Type: model.Pattern, Value: "build/"},
+		{Level: model.Prohibited, Type: model.Pattern, Value: ".gradle/"},
+		{Level: model.Required, Type: model.File, Value: "LICENSE"},
+		{Level: model.Required, Type: model.File, Value: "README.md"},
+	},
+}
+
 // {
 //	"name": "Standard",
 //  "rules" : [
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/jimschubert/ossify/internal/config"
@@ -118,6 +119,76 @@ func TestRule_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestRule_ExpectationJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    model.Rule
+		wantErr bool
+	}{
+		{
+			name: "literal content",
+			data: `{"contains":"<COPYRIGHT HOLDER>","level":"prohibited","type":"content","value":"LICENSE"}`,
+			want: model.Rule{Level: model.Prohibited, Type: model.Content, Value: "LICENSE", Contains: "<COPYRIGHT HOLDER>"},
+		},
+		{
+			name: "regex content",
+			data: `{"level":"required","matches":"^#+\\s*Installation","type":"content","value":"README.md"}`,
+			want: model.Rule{Level: model.Required, Type: model.Content, Value: "README.md", Matches: `^#+\s*Installation`},
+		},
+		{"missing expectation", `{"level":"required","type":"content","value":"README.md"}`, model.Rule{}, true},
+		{"both expectations", `{"contains":"a","level":"required","matches":"b","type":"content","value":"README.md"}`, model.Rule{}, true},
+		{"invalid regex", `{"level":"required","matches":"(","type":"content","value":"README.md"}`, model.Rule{}, true},
+		{
+			name: "pattern with exclude and each",
+			data: `{"each":"main.go","exclude":["cmd/internal/"],"level":"required","type":"pattern","value":"cmd/*/"}`,
+			want: model.Rule{Level: model.Required, Type: model.Pattern, Value: "cmd/*/", Exclude: []string{"cmd/internal/"}, Each: "main.go"},
+		},
+		{"invalid pattern", `{"level":"prohibited","type":"pattern","value":"**/[.orig"}`, model.Rule{}, true},
+		{"invalid exclude", `{"exclude":["{a"],"level":"prohibited","type":"pattern","value":"**/*.orig"}`, model.Rule{}, true},
+		{"each on file rule", `{"each":"main.go","level":"required","type":"file","value":"cmd"}`, model.Rule{}, true},
+		{
+			name: "conditional",
+			data: `{"level":"required","type":"file","value":".dockerignore","when":{"type":"file","value":"Dockerfile"}}`,
+			want: model.Rule{Level: model.Required, Type: model.File, Value: ".dockerignore", When: &model.Condition{Type: model.File, Value: "Dockerfile"}},
+		},
+		{
+			name: "content condition",
+			data: `{"level":"prohibited","type":"directory","value":"vendor","when":{"matches":"^go 1\\.2","type":"content","value":"go.mod"}}`,
+			want: model.Rule{Level: model.Prohibited, Type: model.Directory, Value: "vendor", When: &model.Condition{Type: model.Content, Value: "go.mod", Matches: `^go 1\.2`}},
+		},
+		{"content condition without expectation", `{"level":"required","type":"file","value":"x","when":{"type":"content","value":"go.mod"}}`, model.Rule{}, true},
+		{"invalid condition type", `{"level":"required","type":"file","value":"x","when":{"type":"symlink","value":"y"}}`, model.Rule{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got model.Rule
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Rule.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rule.UnmarshalJSON() = %+v, want %+v", got, tt.want)
+			}
+
+			marshaled, err := got.MarshalJSON()
+			if err != nil {
+				t.Fatalf("Rule.MarshalJSON() error = %v", err)
+			}
+			var roundTrip model.Rule
+			if err := json.Unmarshal(marshaled, &roundTrip); err != nil {
+				t.Fatalf("Rule.UnmarshalJSON() of %s error = %v", marshaled, err)
+			}
+			if !reflect.DeepEqual(roundTrip, tt.want) {
+				t.Errorf("round trip = %+v, want %+v", roundTrip, tt.want)
+			}
+		})
+	}
+}
+
 // helper to create a temp directory with convention files
 func setupTestConventionsDir(t *testing.T, conventions map[string]interface{}) string {
 	t.Helper()
@@ -184,23 +255,23 @@ func TestLoad(t *testing.T) {
 					return &config.Config{ConventionPath: dir}, nil
 				}
 			},
-			wantCount: 2, // Standard Distribution + Go
-			wantNames: []string{"Standard Distribution", "Go"},
+			wantCount: 7, // built-in conventions
+			wantNames: []string{"Standard Distribution", "Go", "Node.js", "Python", "Rust", "Java (Maven)", "Java (Gradle)"},
 			wantErr:   false,
 		},
 		{
 			name: "loads custom conventions from files",
 			setupDir: map[string]interface{}{
-				"nodejs.json": model.Convention{
-					Name: "Node.js",
+				"deno.json": model.Convention{
+					Name: "Deno",
 					Rules: []model.Rule{
-						{Level: model.Required, Type: model.File, Value: "package.json"},
+						{Level: model.Required, Type: model.File, Value: "deno.json"},
 					},
 				},
-				"python.json": model.Convention{
-					Name: "Python",
+				"elixir.json": model.Convention{
+					Name: "Elixir",
 					Rules: []model.Rule{
-						{Level: model.Required, Type: model.File, Value: "setup.py"},
+						{Level: model.Required, Type: model.File, Value: "mix.exs"},
 					},
 				},
 			},
@@ -209,8 +280,8 @@ func TestLoad(t *testing.T) {
 					return &config.Config{ConventionPath: dir}, nil
 				}
 			},
-			wantCount: 4, // 2 defaults + 2 custom
-			wantNames: []string{"Standard Distribution", "Go", "Node.js", "Python"},
+			wantCount: 9, // 7 defaults + 2 custom
+			wantNames: []string{"Standard Distribution", "Go", "Deno", "Elixir"},
 			wantErr:   false,
 		},
 		{
@@ -226,7 +297,7 @@ func TestLoad(t *testing.T) {
 			errContains: "invalid convention path",
 		},
 		{
-			name: "skips invalid JSON files",
+			name: "reports invalid JSON files",
 			setupDir: map[string]interface{}{
 				"valid.json":   model.Convention{Name: "Valid", Rules: []model.Rule{{Level: model.Required, Type: model.File, Value: "README.md"}}},
 				"invalid.json": "this is not valid json {{{",
@@ -236,8 +307,35 @@ func TestLoad(t *testing.T) {
 					return &config.Config{ConventionPath: dir}, nil
 				}
 			},
-			wantCount: 3, // 2 defaults + 1 valid custom
-			wantNames: []string{"Standard Distribution", "Go", "Valid"},
+			wantErr:     true,
+			errContains: "invalid.json:1:2: $: invalid character",
+		},
+		{
+			name: "loads files without an extension as written by earlier versions",
+			setupDir: map[string]interface{}{
+				"legacy": model.Convention{Name: "Legacy", Rules: []model.Rule{{Level: model.Required, Type: model.File, Value: "README.md"}}},
+			},
+			configOverride: func(dir string) config.LoadConfig {
+				return func() (*config.Config, error) {
+					return &config.Config{ConventionPath: dir}, nil
+				}
+			},
+			wantCount: 8,
+			wantNames: []string{"Standard Distribution", "Go", "Legacy"},
+			wantErr:   false,
+		},
+		{
+			name: "loads conventions with warnings",
+			setupDir: map[string]interface{}{
+				"legacy.json": `{"name": "Legacy", "rules": [{"level": "prohibited", "type": "unspecified", "value": ".bak"}]}`,
+			},
+			configOverride: func(dir string) config.LoadConfig {
+				return func() (*config.Config, error) {
+					return &config.Config{ConventionPath: dir}, nil
+				}
+			},
+			wantCount: 8,
+			wantNames: []string{"Standard Distribution", "Go", "Legacy"},
 			wantErr:   false,
 		},
 		{
@@ -251,10 +349,25 @@ func TestLoad(t *testing.T) {
 					return &config.Config{ConventionPath: dir}, nil
 				}
 			},
-			wantCount: 3, // 2 defaults + 1 valid custom (directory skipped)
+			wantCount: 8, // 7 defaults + 1 valid custom (directory skipped)
 			wantNames: []string{"Standard Distribution", "Go", "Valid"},
 			wantErr:   false,
 		},
+		{
+			name: "skips files which are not convention documents",
+			setupDir: map[string]interface{}{
+				"valid.yaml": "name: Valid\nrules:\n  - { level: required, type: file, value: README.md }\n",
+				".DS_Store":  "\x00\x00\x00\x01Bud1",
+				"notes.txt":  "draft conventions",
+			},
+			configOverride: func(dir string) config.LoadConfig {
+				return func() (*config.Config, error) {
+					return &config.Config{ConventionPath: dir}, nil
+				}
+			},
+			wantCount: 8,
+			wantNames: []string{"Standard Distribution", "Valid"},
+		},
 		{
 			name:     "returns defaults when conventions directory does not exist",
 			setupDir: nil,
@@ -263,10 +376,39 @@ func TestLoad(t *testing.T) {
 					return &config.Config{ConventionPath: "/nonexistent/path/that/does/not/exist"}, nil
 				}
 			},
-			wantCount: 2, // only defaults
+			wantCount: 7, // only defaults
 			wantNames: []string{"Standard Distribution", "Go"},
 			wantErr:   false,
 		},
+		{
+			name: "resolves conventions extending built-in conventions",
+			setupDir: map[string]interface{}{
+				"our-go.json": model.Convention{Name: "Our Go", Extends: []string{"Go"}, Rules: []model.Rule{{Level: model.Required, Type: model.File, Value: "CODEOWNERS"}}},
+			},
+			configOverride: func(dir string) config.LoadConfig {
+				return func() (*config.Config, error) {
+					return &config.Config{ConventionPath: dir}, nil
+				}
+			},
+			wantCount: 8,
+			wantNames: []string{"Standard Distribution", "Go", "Our Go"},
+			wantErr:   false,
+		},
+		{
+			name: "returns error for inheritance cycles",
+			setupDir: map[string]interface{}{
+				"a.json": model.Convention{Name: "A", Extends: []string{"B"}},
+				"b.json": model.Convention{Name: "B", Extends: []string{"A"}},
+			},
+			configOverride: func(dir string) config.LoadConfig {
+				return func() (*config.Config, error) {
+					return &config.Config{ConventionPath: dir}, nil
+				}
+			},
+			wantCount:   0,
+			wantErr:     true,
+			errContains: "cycle",
+		},
 		{
 			name:     "returns error when config manager fails to load",
 			setupDir: nil,
@@ -345,6 +487,508 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestResolve(t *testing.T) {
+	base := model.Convention{
+		Name: "Base",
+		Rules: []model.Rule{
+			{Level: model.Required, Type: model.File, Value: "LICENSE"},
+			{Level: model.Required, Type: model.File, Value: "README.md"},
+			{Level: model.Required, Type: model.Directory, Value: "docs"},
+			{Level: model.Prohibited, Type: model.Content, Value: "LICENSE", Contains: "<OWNER>"},
+		},
+	}
+	security := model.Convention{
+		Name: "Security",
+		Rules: []model.Rule{
+			{Level: model.Required, Type: model.File, Value: "SECURITY.md"},
+			{Level: model.Preferred, Type: model.Directory, Value: "docs"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		conventions []model.Convention // the last convention is the one checked against want
+		want        []model.Rule
+		errContains string
+	}{
+		{
+			name: "merges parents in order and overrides by value",
+			conventions: []model.Convention{base, security, {
+				Name:    "Team",
+				Extends: []string{"base", "Security"},
+				Rules: []model.Rule{
+					{Level: model.Optional, Type: model.File, Value: "README.md"},
+					{Level: model.Required, Type: model.Content, Value: "LICENSE", Contains: "MIT"},
+				},
+			}},
+			want: []model.Rule{
+				{Level: model.Required, Type: model.File, Value: "LICENSE"},
+				{Level: model.Optional, Type: model.File, Value: "README.md"},
+				{Level: model.Preferred, Type: model.Directory, Value: "docs"},
+				{Level: model.Prohibited, Type: model.Content, Value: "LICENSE", Contains: "<OWNER>"},
+				{Level: model.Required, Type: model.File, Value: "SECURITY.md"},
+				{Level: model.Required, Type: model.Content, Value: "LICENSE", Contains: "MIT"},
+			},
+		},
+		{
+			name: "removes inherited rules by value",
+			conventions: []model.Convention{base, {
+				Name:    "Team",
+				Extends: []string{"Base"},
+				Remove:  []string{"LICENSE", "docs"},
+			}},
+			want: []model.Rule{
+				{Level: model.Required, Type: model.File, Value: "README.md"},
+			},
+		},
+		{
+			name: "resolves multiple levels",
+			conventions: []model.Convention{
+				{Name: "Org", Extends: []string{"Security"}, Remove: []string{"docs"}},
+				security,
+				{Name: "Team", Extends: []string{"Org"}, Rules: []model.Rule{{Level: model.Required, Type: model.File, Value: "CODEOWNERS"}}},
+			},
+			want: []model.Rule{
+				{Level: model.Required, Type: model.File, Value: "SECURITY.md"},
+				{Level: model.Required, Type: model.File, Value: "CODEOWNERS"},
+			},
+		},
+		{
+			name: "extends a replaced convention of the same name",
+			conventions: []model.Convention{base, {
+				Name:    "Base",
+				Extends: []string{"Base"},
+				Remove:  []string{"docs", "LICENSE"},
+			}},
+			want: []model.Rule{
+				{Level: model.Required, Type: model.File, Value: "README.md"},
+			},
+		},
+		{
+			name:        "reports unknown parents",
+			conventions: []model.Convention{{Name: "Team", Extends: []string{"Missing"}}},
+			errContains: "unknown convention 'Missing'",
+		},
+		{
+			name: "reports cycles",
+			conventions: []model.Convention{
+				{Name: "A", Extends: []string{"B"}},
+				{Name: "B", Extends: []string{"C"}},
+				{Name: "C", Extends: []string{"A"}},
+			},
+			errContains: "cycle: A -> B -> C -> A",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.conventions)
+			if tt.errContains != "" {
+				if err == nil || !contains(err.Error(), tt.errContains) {
+					t.Errorf("Resolve() error = %v, want error containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			// the convention under test is always the last
+			resolved := got[len(got)-1]
+			if !reflect.DeepEqual(resolved.Rules, tt.want) {
+				t.Errorf("Resolve() rules = %+v, want %+v", resolved.Rules, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"go.mod", "package.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	node := model.Convention{Name: "Node", Detect: []string{"package.json"}}
+	python := model.Convention{Name: "Python", Detect: []string{"pyproject.toml", "setup.py"}}
+
+	tests := []struct {
+		name        string
+		conventions []model.Convention
+		want        []string
+	}{
+		{
+			name:        "selects conventions with matching markers",
+			conventions: []model.Convention{StandardDistributionConvention, GoConvention, node, python},
+			want:        []string{"Go (found go.mod)", "Node (found package.json)"},
+		},
+		{
+			name: "prefers the extending convention",
+			conventions: []model.Convention{GoConvention, node,
+				{Name: "Our Go", Extends: []string{"Go"}},
+				{Name: "Our Service", Extends: []string{"Our Go"}, Detect: []string{"go.*"}},
+			},
+			want: []string{"Node (found package.json)", "Our Service (found go.mod)"},
+		},
+		{
+			name:        "ignores replaced conventions",
+			conventions: []model.Convention{GoConvention, {Name: "go", Detect: []string{"go.sum"}}},
+			want:        []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := Resolve(tt.conventions)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			detections, err := Detect(resolved, dir)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+
+			got := make([]string, 0, len(detections))
+			for _, d := range detections {
+				got = append(got, d.Describe())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectModules(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"services/api/go.mod":             "module api",
+		"web/package.json":                "{}",
+		"web/node_modules/x/package.json": "{}",
+		"tools/lint/pyproject.toml":       "",
+		"generated/go.mod":                "module generated",
+		".gitignore":                      "generated/\n",
+		"docs/README.md":                  "",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(p), err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	modules, err := DetectModules(DefaultConventions, dir)
+	if err != nil {
+		t.Fatalf("DetectModules() error = %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, m := range modules {
+		for _, d := range m.Detections {
+			got[m.Path] = append(got[m.Path], d.Convention.Name)
+		}
+	}
+	want := map[string][]string{
+		"services/api": {"Go"},
+		"tools/lint":   {"Python"},
+		"web":          {"Node.js"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectModules() = %v, want %v", got, want)
+	}
+}
+
+func TestInfer(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"cmd/", "docs/", ".github/", ".idea/", "dist/", "README.md", "LICENSE", "go.mod", "main.go", ".gitignore"} {
+		p := filepath.Join(dir, name)
+		var err error
+		if strings.HasSuffix(name, "/") {
+			err = os.MkdirAll(p, 0755)
+		} else {
+			err = os.WriteFile(p, []byte("dist/\n"), 0644)
+		}
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	convention, err := Infer("Reference", []string{dir}, DefaultThresholds)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if convention.Name != "Reference" {
+		t.Errorf("Infer() name = %s", convention.Name)
+	}
+
+	levels := make(map[string]model.StrictnessLevel)
+	for _, r := range convention.Rules {
+		levels[r.Value] = r.Level
+	}
+	want := map[string]model.StrictnessLevel{
+		".github":      model.Required,
+		"cmd":          model.Required,
+		"docs":         model.Required,
+		"README.md":    model.Required,
+		"LICENSE":      model.Required,
+		"go.mod":       model.Required,
+		".gitignore":   model.Required,
+		"node_modules": model.Prohibited,
+		".vscode":      model.Prohibited,
+		".DS_Store":    model.Prohibited,
+	}
+	for value, level := range want {
+		if got, ok := levels[value]; !ok || got != level {
+			t.Errorf("rule %s = %v (present %v), want %s", value, got, ok, level)
+		}
+	}
+	// main.go is not a well-known file, .idea is present junk, and dist/ is ignored
+	for _, value := range []string{"main.go", ".idea", "dist"} {
+		if _, ok := levels[value]; ok {
+			t.Errorf("unexpected rule for %s", value)
+		}
+	}
+}
+
+func TestInfer_IgnoredJunk(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"node_modules/", "bin/", ".DS_Store", "README.md", ".gitignore"} {
+		p := filepath.Join(dir, name)
+		var err error
+		if strings.HasSuffix(name, "/") {
+			err = os.MkdirAll(p, 0755)
+		} else {
+			err = os.WriteFile(p, []byte("node_modules/\nbin/\n.DS_Store\n"), 0644)
+		}
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	convention, err := Infer("Reference", []string{dir}, DefaultThresholds)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+
+	values := make(map[string]bool)
+	for _, r := range convention.Rules {
+		values[r.Value] = true
+	}
+	// ignored junk is neither present nor prohibited, so the reference repository passes its own convention
+	for _, value := range []string{"node_modules", "bin", ".DS_Store"} {
+		if values[value] {
+			t.Errorf("unexpected rule for ignored %s", value)
+		}
+	}
+	if !values[".vscode"] {
+		t.Errorf("expected absent junk .vscode to be prohibited")
+	}
+
+	results, err := convention.Evaluate(dir)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if results.FailCount != 0 {
+		t.Errorf("reference repository fails its inferred convention: %+v", results.Results)
+	}
+}
+
+func TestInfer_Thresholds(t *testing.T) {
+	repos := [][]string{
+		{"docs/", "README.md", "LICENSE", "CHANGELOG.md"},
+		{"docs/", "README.md", "LICENSE", ".idea/"},
+		{"docs/", "scripts/", "README.md", "LICENSE"},
+		{"README.md", "CODEOWNERS"},
+	}
+	var dirs []string
+	for _, repo := range repos {
+		dir := t.TempDir()
+		for _, name := range repo {
+			p := filepath.Join(dir, name)
+			var err error
+			if strings.HasSuffix(name, "/") {
+				err = os.MkdirAll(p, 0755)
+			} else {
+				err = os.WriteFile(p, nil, 0644)
+			}
+			if err != nil {
+				t.Fatalf("failed to create %s: %v", name, err)
+			}
+		}
+		dirs = append(dirs, dir)
+	}
+
+	tests := []struct {
+		name       string
+		thresholds Thresholds
+		want       map[string]model.StrictnessLevel
+		wantErr    bool
+	}{
+		{
+			name:       "defaults",
+			thresholds: DefaultThresholds,
+			want: map[string]model.StrictnessLevel{
+				"README.md": model.Required, "LICENSE": model.Preferred, "docs": model.Preferred,
+				"scripts": model.Optional, "CHANGELOG.md": model.Optional, "CODEOWNERS": model.Optional,
+				".vscode": model.Prohibited,
+			},
+		},
+		{
+			name:       "lower thresholds",
+			thresholds: Thresholds{Required: 0.75, Preferred: 0.25},
+			want: map[string]model.StrictnessLevel{
+				"README.md": model.Required, "LICENSE": model.Required, "docs": model.Required,
+				"scripts": model.Preferred, "CODEOWNERS": model.Preferred,
+			},
+		},
+		{name: "preferred above required", thresholds: Thresholds{Required: 0.5, Preferred: 0.75}, wantErr: true},
+		{name: "not a fraction", thresholds: Thresholds{Required: 2, Preferred: 0.5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			convention, err := Infer("Org", dirs, tt.thresholds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Infer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			levels := make(map[string]model.StrictnessLevel)
+			for _, r := range convention.Rules {
+				levels[r.Value] = r.Level
+			}
+			for value, level := range tt.want {
+				if got, ok := levels[value]; !ok || got != level {
+					t.Errorf("rule %s = %v (present %v), want %s", value, got, ok, level)
+				}
+			}
+			// junk present in any repository is neither prohibited nor expected
+			if _, ok := levels[".idea"]; ok {
+				t.Errorf("unexpected rule for .idea")
+			}
+		})
+	}
+}
+
+func TestDefaultConventions_Ecosystems(t *testing.T) {
+	tests := []struct {
+		name       string
+		convention model.Convention
+		compliant  []string // files of a project following the convention
+		gitignore  string   // content of .gitignore, when set
+		violations []string // additional files which make the project violate the convention
+		wantFailed []string // values of the rules failing once violations are added
+	}{
+		{
+			name:       "Node.js",
+			convention: NodeConvention,
+			compliant:  []string{"package.json", "package-lock.json", ".gitignore", "LICENSE", "README.md", "index.js"},
+			violations: []string{"node_modules/left-pad/index.js"},
+			wantFailed: []string{"node_modules/"},
+		},
+		{
+			name:       "Node.js with ignored node_modules",
+			convention: NodeConvention,
+			compliant:  []string{"package.json", "yarn.lock", ".gitignore", "LICENSE", "README.md", "node_modules/left-pad/index.js"},
+			gitignore:  "node_modules/\n",
+		},
+		{
+			name:       "Python",
+			convention: PythonConvention,
+			compliant:  []string{"pyproject.toml", "src/pkg/__init__.py", "tests/test_pkg.py", "LICENSE", "README.md"},
+			violations: []string{"src/pkg/__pycache__/x.pyc", "pkg.egg-info/PKG-INFO"},
+			wantFailed: []string{"**/__pycache__/", "*.egg-info/"},
+		},
+		{
+			name:       "Rust",
+			convention: RustConvention,
+			compliant:  []string{"Cargo.toml", "src/main.rs", "LICENSE-MIT", "LICENSE-APACHE", "README.md"},
+			violations: []string{"target/debug/app"},
+			wantFailed: []string{"target/"},
+		},
+		{
+			name:       "Java (Maven)",
+			convention: MavenConvention,
+			compliant:  []string{"pom.xml", "src/main/java/App.java", "src/test/java/AppTest.java", "LICENSE", "README.md"},
+			violations: []string{"target/app.jar"},
+			wantFailed: []string{"target/"},
+		},
+		{
+			name:       "Java (Gradle)",
+			convention: GradleConvention,
+			compliant:  []string{"build.gradle.kts", "settings.gradle.kts", "gradlew", "gradle/wrapper/gradle-wrapper.properties", "src/main/kotlin/App.kt", "src/test/kotlin/AppTest.kt", "LICENSE", "README.md"},
+			violations: []string{"build/libs/app.jar", ".gradle/cache"},
+			wantFailed: []string{"build/", ".gradle/"},
+		},
+	}
+
+	write := func(t *testing.T, dir string, names []string) {
+		t.Helper()
+		for _, name := range names {
+			fullPath := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+				t.Fatalf("failed to create parent dir for %s: %v", name, err)
+			}
+			if err := os.WriteFile(fullPath, nil, 0644); err != nil {
+				t.Fatalf("failed to write %s: %v", name, err)
+			}
+		}
+	}
+
+	failed := func(t *testing.T, convention model.Convention, dir string) []string {
+		t.Helper()
+		result, err := convention.Evaluate(dir)
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		var values []string
+		for _, r := range result.Results {
+			if r.Status() != model.StatusPass {
+				values = append(values, r.Rule.Value)
+			}
+		}
+		return values
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			write(t, dir, tt.compliant)
+			if tt.gitignore != "" {
+				if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(tt.gitignore), 0644); err != nil {
+					t.Fatalf("failed to write .gitignore: %v", err)
+				}
+			}
+
+			detections, err := Detect(DefaultConventions, dir)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if len(detections) != 1 || detections[0].Convention.Name != tt.convention.Name {
+				t.Errorf("Detect() = %+v, want only %s", detections, tt.convention.Name)
+			}
+
+			if got := failed(t, tt.convention, dir); len(got) > 0 {
+				t.Errorf("compliant project failed rules %v", got)
+			}
+
+			if len(tt.violations) == 0 {
+				return
+			}
+			write(t, dir, tt.violations)
+			if got := failed(t, tt.convention, dir); !reflect.DeepEqual(got, tt.wantFailed) {
+				t.Errorf("failed rules = %v, want %v", got, tt.wantFailed)
+			}
+		})
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
 		(len(s) > 0 && len(substr) > 0 && searchSubstring(s, substr)))
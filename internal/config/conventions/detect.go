@@ -0,0 +1,67 @@
+package conventions
+
+import (
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+// Detection is a convention selected for a directory, with the paths of the markers which selected it
+type Detection struct {
+	Convention model.Convention
+	Markers    []string
+}
+
+// Detect selects the conventions which apply to dir by their Detect markers. A convention replaced by a later
+// convention of the same name is never selected, and when a convention and one it extends are both detected,
+// only the extending (more specific) convention is selected.
+func Detect(all []model.Convention, dir string) ([]Detection, error) {
+	r := &resolver{all: all}
+
+	var detected []int
+	markers := make(map[int][]string)
+	for i := range all {
+		if r.find(all[i].Name, i) > i {
+			continue
+		}
+		found, err := all[i].Detected(dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) > 0 {
+			detected = append(detected, i)
+			markers[i] = found
+		}
+	}
+
+	ancestors := make(map[int]bool)
+	for _, i := range detected {
+		r.ancestors(i, ancestors)
+	}
+
+	detections := make([]Detection, 0, len(detected))
+	for _, i := range detected {
+		if ancestors[i] {
+			continue
+		}
+		detections = append(detections, Detection{Convention: all[i], Markers: markers[i]})
+	}
+	return detections, nil
+}
+
+// ancestors adds the indexes of every convention i extends, directly or indirectly, to seen
+func (r *resolver) ancestors(i int, seen map[int]bool) {
+	for _, parentName := range r.all[i].Extends {
+		parent := r.find(parentName, i)
+		if parent == -1 || seen[parent] {
+			continue
+		}
+		seen[parent] = true
+		r.ancestors(parent, seen)
+	}
+}
+
+// Describe explains why a convention was detected, e.g. "Go (found go.mod)"
+func (d Detection) Describe() string {
+	return d.Convention.Name + " (found " + strings.Join(d.Markers, ", ") + ")"
+}
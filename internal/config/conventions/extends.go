@@ -0,0 +1,168 @@
+package conventions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+// Resolve returns a copy of all in which each convention's rules are merged with the rules of the conventions
+// it extends. Parents are looked up by name (case-insensitive) among all; when several conventions share a name,
+// the last one wins, which lets a user convention both replace and extend a built-in convention of the same name.
+//
+// Parent rules are merged in the order the parents are listed, then rules whose values are listed in Remove are
+// dropped, and finally the convention's own rules are applied. A rule overrides an earlier rule targeting the same
+// value, keeping the earlier rule's position; content rules only override content rules with the same expectation.
+// A convention without Detect markers inherits the markers of its parents.
+func Resolve(all []model.Convention) ([]model.Convention, error) {
+	r := &resolver{
+		all:      all,
+		resolved: make(map[int][]model.Rule, len(all)),
+		visiting: make(map[int]bool),
+	}
+
+	conventions := make([]model.Convention, len(all))
+	for i := range all {
+		rules, err := r.rules(i, nil)
+		if err != nil {
+			return nil, err
+		}
+		conventions[i] = all[i]
+		conventions[i].Rules = rules
+	}
+	// cycles were reported while resolving rules, so markers can be inherited without further checks
+	for i := range conventions {
+		conventions[i].Detect = r.detect(i)
+	}
+	return conventions, nil
+}
+
+type resolver struct {
+	all      []model.Convention
+	resolved map[int][]model.Rule
+	visiting map[int]bool
+}
+
+func (r *resolver) rules(i int, chain []string) ([]model.Rule, error) {
+	if rules, ok := r.resolved[i]; ok {
+		return rules, nil
+	}
+
+	convention := r.all[i]
+	chain = append(chain, convention.Name)
+	if r.visiting[i] {
+		return nil, fmt.Errorf("convention inheritance cycle: %s", strings.Join(chain, " -> "))
+	}
+	if len(convention.Extends) == 0 && len(convention.Remove) == 0 {
+		r.resolved[i] = convention.Rules
+		return convention.Rules, nil
+	}
+
+	r.visiting[i] = true
+	defer delete(r.visiting, i)
+
+	var merged []model.Rule
+	for _, parentName := range convention.Extends {
+		parent := r.find(parentName, i)
+		if parent == -1 {
+			return nil, fmt.Errorf("convention '%s' extends unknown convention '%s'", convention.Name, parentName)
+		}
+		parentRules, err := r.rules(parent, chain)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeRules(merged, parentRules)
+	}
+
+	merged = removeRules(merged, convention.Remove)
+	merged = mergeRules(merged, convention.Rules)
+
+	r.resolved[i] = merged
+	return merged, nil
+}
+
+// detect returns the Detect markers of convention i, inherited from its parents when it has none
+func (r *resolver) detect(i int) []string {
+	convention := r.all[i]
+	if len(convention.Detect) > 0 || len(convention.Extends) == 0 {
+		return convention.Detect
+	}
+
+	var markers []string
+	for _, parentName := range convention.Extends {
+		for _, marker := range r.detect(r.find(parentName, i)) {
+			if !slices.Contains(markers, marker) {
+				markers = append(markers, marker)
+			}
+		}
+	}
+	return markers
+}
+
+// find returns the index of the last convention named name, other than self, or -1
+func (r *resolver) find(name string, self int) int {
+	for i := len(r.all) - 1; i >= 0; i-- {
+		if i != self && strings.EqualFold(r.all[i].Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeRules applies overrides on top of base, replacing rules with the same key in place and appending the rest
+func mergeRules(base []model.Rule, overrides []model.Rule) []model.Rule {
+	merged := make([]model.Rule, len(base), len(base)+len(overrides))
+	copy(merged, base)
+
+	index := make(map[string]int, len(merged))
+	for i, rule := range merged {
+		index[ruleKey(rule)] = i
+	}
+
+	for _, rule := range overrides {
+		key := ruleKey(rule)
+		if i, ok := index[key]; ok {
+			merged[i] = rule
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, rule)
+	}
+	return merged
+}
+
+// removeRules drops every rule targeting one of values
+func removeRules(rules []model.Rule, values []string) []model.Rule {
+	if len(values) == 0 {
+		return rules
+	}
+
+	remove := make(map[string]bool, len(values))
+	for _, v := range values {
+		remove[v] = true
+	}
+
+	kept := make([]model.Rule, 0, len(rules))
+	for _, rule := range rules {
+		if !remove[rule.Value] {
+			kept = append(kept, rule)
+		}
+	}
+	return kept
+}
+
+// ruleKey identifies the item a rule targets. Directory, file and pattern rules on the same value describe
+// the same item, while a file may have several independent content rules. A conditional rule is a separate
+// requirement from an unconditional rule on the same item.
+func ruleKey(rule model.Rule) string {
+	key := rule.Value
+	if rule.Type == model.Content {
+		key = strings.Join([]string{"content", rule.Value, rule.Contains, rule.Matches}, "\x00")
+	}
+	if rule.When != nil {
+		key += "\x00when " + rule.When.String()
+	}
+	return key
+}
@@ -0,0 +1,271 @@
+package conventions
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jimschubert/ossify/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the serialization of a convention document
+type Format string
+
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+	TOML Format = "toml"
+)
+
+// Formats are the supported convention document formats
+var Formats = []Format{JSON, YAML, TOML}
+
+// Extension is the file extension of documents in the format, e.g. ".yaml"
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// String is the conventional spelling of the format, e.g. "YAML"
+func (f Format) String() string {
+	return strings.ToUpper(string(f))
+}
+
+var (
+	// tomlLine matches a TOML table header or key/value pair
+	tomlLine = regexp.MustCompile(`^(\[\[?\s*[\w".-]+\s*]]?|[\w"-]+\s*=)`)
+	// yamlErrorLine extracts the line number from a yaml.v3 error
+	yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+)
+
+// DetectFormat chooses the format of the document data read from file by its extension (.json, .yaml, .yml or
+// .toml), falling back to its content: an object is JSON, table headers or key/value pairs are TOML, and anything
+// else is YAML
+func DetectFormat(file string, data []byte) Format {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return JSON
+	case ".yaml", ".yml":
+		return YAML
+	case ".toml":
+		return TOML
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "{"):
+			return JSON
+		case tomlLine.MatchString(line):
+			return TOML
+		default:
+			return YAML
+		}
+	}
+	return JSON
+}
+
+// Decode parses the convention document data read from file in the format chosen by DetectFormat
+func Decode(file string, data []byte) (*model.Convention, error) {
+	format := DetectFormat(file, data)
+	converted, err := toJSON(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", format, err)
+	}
+
+	var convention model.Convention
+	if err := json.Unmarshal(converted, &convention); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", format, err)
+	}
+	return &convention, nil
+}
+
+// toJSON converts a document in format to JSON, so that every format is decoded and validated alike
+func toJSON(format Format, data []byte) ([]byte, error) {
+	var document any
+	switch format {
+	case YAML:
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+	case TOML:
+		table := make(map[string]any)
+		if _, err := toml.Decode(string(data), &table); err != nil {
+			return nil, err
+		}
+		document = table
+	default:
+		return data, nil
+	}
+	return json.Marshal(normalize(document))
+}
+
+// normalize converts the maps with non-string keys which YAML allows into maps which can be written as JSON
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalize(item)
+		}
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+	case []map[string]any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = normalize(item)
+		}
+		return items
+	}
+	return value
+}
+
+// conversionError locates an error converting a YAML or TOML document
+func conversionError(file string, err error) Diagnostic {
+	d := Diagnostic{File: file, Path: "$", Line: 1, Column: 1, Message: err.Error()}
+	var tomlErr toml.ParseError
+	if errors.As(err, &tomlErr) {
+		d.Line, d.Column, d.Message = tomlErr.Position.Line, max(1, tomlErr.Position.Col), tomlErr.Message
+	} else if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+		d.Line, _ = strconv.Atoi(match[1])
+	}
+	return d
+}
+
+// pathSegments splits a JSON path such as "$.rules[2].level" into field names and array indexes
+func pathSegments(path string) []any {
+	var segments []any
+	for _, part := range strings.Split(strings.TrimPrefix(path, "$"), ".") {
+		for part != "" {
+			open := strings.IndexByte(part, '[')
+			if open < 0 {
+				segments = append(segments, part)
+				break
+			}
+			if open > 0 {
+				segments = append(segments, part[:open])
+			}
+			end := strings.IndexByte(part, ']')
+			index, _ := strconv.Atoi(part[open+1 : end])
+			segments = append(segments, index)
+			part = part[end+1:]
+		}
+	}
+	return segments
+}
+
+// yamlPosition is the line and column of the value at path in a YAML document, or of its closest located parent
+func yamlPosition(data []byte, path string) (int, int) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return 1, 1
+	}
+	n := root.Content[0]
+	line, column := n.Line, n.Column
+	for _, segment := range pathSegments(path) {
+		var next *yaml.Node
+		switch s := segment.(type) {
+		case string:
+			if n.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(n.Content); i += 2 {
+					if n.Content[i].Value == s {
+						next = n.Content[i+1]
+					}
+				}
+			}
+		case int:
+			if n.Kind == yaml.SequenceNode && s < len(n.Content) {
+				next = n.Content[s]
+			}
+		}
+		if next == nil {
+			break
+		}
+		n = next
+		line, column = n.Line, n.Column
+	}
+	return line, column
+}
+
+// tomlPosition is the line and column of the key at path in a TOML document, or of its closest located parent.
+// TOML parsers do not expose positions, so keys are found by line: fields of the convention before the first
+// table, and fields of the nth rule in the nth [[rules]] table.
+func tomlPosition(data []byte, path string) (int, int) {
+	lines := strings.Split(string(data), "\n")
+
+	// keyLine finds the line in lines[from:to] which assigns key
+	keyLine := func(key string, from, to int) int {
+		for i := from; i < to; i++ {
+			if name, _, ok := strings.Cut(lines[i], "="); ok && strings.Trim(name, " \t\"") == key {
+				return i
+			}
+		}
+		return -1
+	}
+	position := func(i int) (int, int) {
+		return i + 1, len(lines[i]) - len(strings.TrimLeft(lines[i], " \t")) + 1
+	}
+
+	// the convention's own fields end at the first table
+	end := len(lines)
+	var tables []int
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			end = min(end, i)
+			if strings.HasPrefix(line, "[[") && strings.Trim(line, "[] ") == "rules" {
+				tables = append(tables, i)
+			}
+		}
+	}
+
+	segments := pathSegments(path)
+	if len(segments) == 0 {
+		return 1, 1
+	}
+	name, _ := segments[0].(string)
+	if i := keyLine(name, 0, end); i >= 0 || name != "rules" || len(tables) == 0 {
+		if i < 0 {
+			return 1, 1
+		}
+		return position(i)
+	}
+
+	// rules declared as [[rules]] tables
+	if len(segments) < 2 {
+		return position(tables[0])
+	}
+	index, _ := segments[1].(int)
+	if index >= len(tables) {
+		return position(tables[len(tables)-1])
+	}
+	next := len(lines)
+	if index+1 < len(tables) {
+		next = tables[index+1]
+	}
+	if len(segments) > 2 {
+		field, _ := segments[2].(string)
+		if i := keyLine(field, tables[index]+1, next); i >= 0 {
+			return position(i)
+		}
+	}
+	return position(tables[index])
+}
@@ -0,0 +1,122 @@
+package conventions
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		file string
+		data string
+		want Format
+	}{
+		{"go.json", "name: Go", JSON},
+		{"go.yaml", "{}", YAML},
+		{"go.YML", "", YAML},
+		{"go.toml", "", TOML},
+		{"", "  {\"name\": \"Go\"}", JSON},
+		{"", "# our layout\nname = \"Go\"", TOML},
+		{"", "[[rules]]\nlevel = \"required\"", TOML},
+		{"go", "# our layout\nname: Go\n", YAML},
+		{"", "---\nname: Go\n", YAML},
+		{"", "", JSON},
+	}
+	for _, tt := range tests {
+		if got := DetectFormat(tt.file, []byte(tt.data)); got != tt.want {
+			t.Errorf("DetectFormat(%q, %q) = %s, want %s", tt.file, tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	want := model.Convention{
+		Name:    "Our Go",
+		Extends: []string{"Go"},
+		Rules: []model.Rule{
+			{Level: model.Required, Type: model.File, Value: "CODEOWNERS"},
+			{Level: model.Prohibited, Type: model.Content, Value: "README.md", Contains: "TODO"},
+			{Level: model.Prohibited, Type: model.Pattern, Value: "**/*.orig", Exclude: []string{"testdata/"}},
+		},
+	}
+
+	documents := map[string]string{
+		"go.json": `{
+  "name": "Our Go",
+  "extends": ["Go"],
+  "rules": [
+    {"level": "required", "type": "file", "value": "CODEOWNERS"},
+    {"level": "prohibited", "type": "content", "value": "README.md", "contains": "TODO"},
+    {"level": "prohibited", "type": "pattern", "value": "**/*.orig", "exclude": ["testdata/"]}
+  ]
+}`,
+		"go.yaml": `# reviewed by the platform team
+name: Our Go
+extends: [Go]
+rules:
+  - level: required
+    type: file
+    value: CODEOWNERS
+  - { level: prohibited, type: content, value: README.md, contains: TODO }
+  - level: prohibited
+    type: pattern
+    value: "**/*.orig"
+    exclude:
+      - testdata/
+`,
+		"go.toml": `# reviewed by the platform team
+name = "Our Go"
+extends = ["Go"]
+
+[[rules]]
+level = "required"
+type = "file"
+value = "CODEOWNERS"
+
+[[rules]]
+level = "prohibited"
+type = "content"
+value = "README.md"
+contains = "TODO"
+
+[[rules]]
+level = "prohibited"
+type = "pattern"
+value = "**/*.orig"
+exclude = ["testdata/"]
+`,
+	}
+
+	for file, data := range documents {
+		t.Run(file, func(t *testing.T) {
+			got, err := Decode(file, []byte(data))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("Decode() = %+v, want %+v", *got, want)
+			}
+			if diagnostics := Validate(file, []byte(data)); len(diagnostics) > 0 {
+				t.Errorf("Validate() = %v", diagnostics)
+			}
+
+			// the level and type names round-trip through JSON
+			output, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var again model.Convention
+			if err := json.Unmarshal(output, &again); err != nil || !reflect.DeepEqual(again, want) {
+				t.Errorf("round-trip = %+v, %v", again, err)
+			}
+		})
+	}
+
+	if _, err := Decode("go.yaml", []byte("name: [")); err == nil || !strings.HasPrefix(err.Error(), "invalid YAML:") {
+		t.Errorf("Decode() error = %v, want an invalid YAML error", err)
+	}
+}
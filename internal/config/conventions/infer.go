@@ -0,0 +1,131 @@
+package conventions
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/jimschubert/ossify/internal/model"
+	"github.com/jimschubert/ossify/internal/pathmatch"
+)
+
+// WellKnownFiles are top-level files whose presence in a reference repository is part of its convention
+var WellKnownFiles = []string{
+	"README.md", "LICENSE", "CONTRIBUTING.md", "CODE_OF_CONDUCT.md", "SECURITY.md", "CHANGELOG.md", "CODEOWNERS",
+	"NOTICE", "AUTHORS", ".gitignore", ".gitattributes", ".editorconfig", "Makefile", "Dockerfile",
+	"go.mod", "package.json", "pyproject.toml", "setup.py", "Cargo.toml", "pom.xml", "build.gradle", "build.gradle.kts",
+}
+
+// JunkDirectories are top-level build outputs, dependency caches and IDE folders which should not be committed
+var JunkDirectories = []string{
+	".idea", ".vscode", ".vs", ".settings", "node_modules", "__pycache__", ".pytest_cache", ".gradle",
+	"target", "bin", "obj", "out", "coverage",
+}
+
+// JunkFiles are top-level files left by operating systems and editors which should not be committed
+var JunkFiles = []string{".DS_Store", "Thumbs.db", ".project", ".classpath"}
+
+// Thresholds are the fractions of reference repositories in which an item must be present for Infer to make it
+// required or preferred; an item present in fewer repositories is optional
+type Thresholds struct {
+	Required  float64
+	Preferred float64
+}
+
+// DefaultThresholds require items present in every reference repository and prefer items present in at least half
+var DefaultThresholds = Thresholds{Required: 1, Preferred: 0.5}
+
+// Validate reports whether the thresholds are fractions with Preferred no greater than Required
+func (t Thresholds) Validate() error {
+	if t.Required <= 0 || t.Required > 1 || t.Preferred <= 0 || t.Preferred > 1 {
+		return fmt.Errorf("thresholds must be greater than 0 and at most 1")
+	}
+	if t.Preferred > t.Required {
+		return fmt.Errorf("preferred threshold %g is greater than required threshold %g", t.Preferred, t.Required)
+	}
+	return nil
+}
+
+// level is the level of an item present in the given fraction of reference repositories
+func (t Thresholds) level(fraction float64) model.StrictnessLevel {
+	switch {
+	case fraction >= t.Required:
+		return model.Required
+	case fraction >= t.Preferred:
+		return model.Preferred
+	default:
+		return model.Optional
+	}
+}
+
+// Infer proposes a convention named name from the top level of the reference repositories at dirs. Directories
+// and well-known files are required, preferred or optional by the fraction of the repositories which have them,
+// and junk which none of them has is prohibited. The .git directory and paths ignored by .gitignore are not
+// considered present, although junk ignored by .gitignore is not prohibited either since prohibited directories
+// and files are checked on disk.
+func Infer(name string, dirs []string, thresholds Thresholds) (*model.Convention, error) {
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no reference repositories to infer from")
+	}
+	if err := thresholds.Validate(); err != nil {
+		return nil, err
+	}
+
+	// count the repositories having each top-level directory and file
+	dirCounts := make(map[string]int)
+	fileCounts := make(map[string]int)
+	for _, dir := range dirs {
+		err := pathmatch.Walk(dir, func(rel string, d fs.DirEntry) error {
+			if d.IsDir() {
+				dirCounts[rel]++
+				return fs.SkipDir
+			}
+			fileCounts[rel]++
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fraction := func(count int) float64 {
+		return float64(count) / float64(len(dirs))
+	}
+
+	var rules []model.Rule
+	for _, d := range slices.Sorted(maps.Keys(dirCounts)) {
+		if !slices.Contains(JunkDirectories, d) {
+			rules = append(rules, model.Rule{Level: thresholds.level(fraction(dirCounts[d])), Type: model.Directory, Value: d})
+		}
+	}
+	for _, f := range WellKnownFiles {
+		if count := fileCounts[f]; count > 0 {
+			rules = append(rules, model.Rule{Level: thresholds.level(fraction(count)), Type: model.File, Value: f})
+		}
+	}
+	for _, d := range JunkDirectories {
+		if dirCounts[d] == 0 && !existsInAny(dirs, d) {
+			rules = append(rules, model.Rule{Level: model.Prohibited, Type: model.Directory, Value: d})
+		}
+	}
+	for _, f := range JunkFiles {
+		if fileCounts[f] == 0 && !existsInAny(dirs, f) {
+			rules = append(rules, model.Rule{Level: model.Prohibited, Type: model.File, Value: f})
+		}
+	}
+
+	return model.NewConvention(name, rules), nil
+}
+
+// existsInAny reports whether name exists on disk at the top level of any of dirs, whether or not it is ignored
+func existsInAny(dirs []string, name string) bool {
+	for _, dir := range dirs {
+		if _, err := os.Lstat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package conventions
+
+import (
+	"io/fs"
+	"path/filepath"
+
+	"github.com/jimschubert/ossify/internal/model"
+	"github.com/jimschubert/ossify/internal/pathmatch"
+)
+
+// Module is a project root within a larger tree, with the conventions detected for it
+type Module struct {
+	// Path is the slash-separated path of the module relative to the walked root, or "." for the root itself
+	Path       string
+	Detections []Detection
+}
+
+// skippedModuleDirs are directories holding dependencies or build output rather than modules of the project,
+// which are not searched even when they are not ignored by .gitignore
+var skippedModuleDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"venv":         true,
+	".venv":        true,
+}
+
+// DetectModules walks root, skipping .git, ignored paths and dependency directories, and returns every
+// directory (including root) for which a convention is detected, in walk order
+func DetectModules(all []model.Convention, root string) ([]Module, error) {
+	var modules []Module
+	visit := func(rel string) error {
+		detections, err := Detect(all, filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		if len(detections) > 0 {
+			modules = append(modules, Module{Path: rel, Detections: detections})
+		}
+		return nil
+	}
+
+	if err := visit("."); err != nil {
+		return nil, err
+	}
+	err := pathmatch.Walk(root, func(rel string, d fs.DirEntry) error {
+		if !d.IsDir() {
+			return nil
+		}
+		if skippedModuleDirs[d.Name()] {
+			return fs.SkipDir
+		}
+		return visit(rel)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return modules, nil
+}
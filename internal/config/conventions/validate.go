@@ -0,0 +1,551 @@
+package conventions
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/model"
+	"github.com/jimschubert/ossify/internal/pathmatch"
+	"github.com/jimschubert/ossify/internal/schema"
+)
+
+// Diagnostic is a problem found in a convention document, located by JSON path, line and column
+type Diagnostic struct {
+	File string
+	// Path is the JSON path of the offending value, e.g. "$.rules[2].level"
+	Path    string
+	Line    int
+	Column  int
+	Message string
+	// Warning marks a problem which does not prevent the convention from loading, e.g. a deprecated name
+	Warning bool
+}
+
+// String formats the diagnostic as "file:line:column: path: message", prefixing the message of a warning
+func (d Diagnostic) String() string {
+	if d.Warning {
+		return fmt.Sprintf("%s:%d:%d: %s: warning: %s", d.File, d.Line, d.Column, d.Path, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Column, d.Path, d.Message)
+}
+
+// Errors returns the diagnostics which are not warnings
+func Errors(diagnostics []Diagnostic) []Diagnostic {
+	var errs []Diagnostic
+	for _, d := range diagnostics {
+		if !d.Warning {
+			errs = append(errs, d)
+		}
+	}
+	return errs
+}
+
+// ValidationError reports the diagnostics of one or more invalid convention documents
+type ValidationError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		lines[i] = d.String()
+	}
+	return "invalid conventions:\n  " + strings.Join(lines, "\n  ")
+}
+
+// ruleID matches the identifiers rules may be given, which are used as SARIF rule IDs
+var ruleID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ConventionSchema is the JSON Schema of convention documents, which Validate checks before the rules themselves
+var ConventionSchema = schema.Document(model.Convention{}, "ossify convention")
+
+// Validate checks the convention document data read from file, in the format chosen by DetectFormat. Beyond what
+// unmarshalling checks, it reports unknown fields, empty values, duplicate rules and rules requiring and
+// prohibiting the same item. It returns no diagnostics for a valid document.
+func Validate(file string, data []byte) []Diagnostic {
+	return validate(file, data, false)
+}
+
+// ValidateSchema checks only that the convention document data read from file matches ConventionSchema, as an
+// editor using the published schema would
+func ValidateSchema(file string, data []byte) []Diagnostic {
+	return validate(file, data, true)
+}
+
+func validate(file string, data []byte, schemaOnly bool) []Diagnostic {
+	var diagnostics []Diagnostic
+	if format := DetectFormat(file, data); format == JSON {
+		diagnostics = validateJSON(file, data, schemaOnly)
+	} else {
+		converted, err := toJSON(format, data)
+		if err != nil {
+			return []Diagnostic{conversionError(file, err)}
+		}
+		// the converted document is validated, and each diagnostic moved to the position of its path in data
+		diagnostics = validateJSON(file, converted, schemaOnly)
+		for i, d := range diagnostics {
+			if format == YAML {
+				d.Line, d.Column = yamlPosition(data, d.Path)
+			} else {
+				d.Line, d.Column = tomlPosition(data, d.Path)
+			}
+			diagnostics[i] = d
+		}
+	}
+
+	// schema and rule checks, and converted documents ordering fields by name, would otherwise mix up the order
+	slices.SortStableFunc(diagnostics, func(a, b Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+	return diagnostics
+}
+
+func validateJSON(file string, data []byte, schemaOnly bool) []Diagnostic {
+	v := &validator{file: file, data: data, invalid: make(map[*node]bool), schemaOnly: schemaOnly}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	root, err := v.parse(decoder, "$")
+	if err == nil {
+		v.errPath = "$"
+		if _, err = decoder.Token(); err == nil {
+			v.add("$", int(decoder.InputOffset()), "unexpected data after the convention")
+			return v.diagnostics
+		} else if err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		offset := int(decoder.InputOffset())
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			// the offset follows the offending character
+			offset = max(0, int(syntaxErr.Offset)-1)
+		}
+		message := err.Error()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			message = "unexpected end of JSON input"
+		}
+		v.add(v.errPath, offset, "%s", message)
+		return v.diagnostics
+	}
+
+	if schemaOnly {
+		v.schema(root, ConventionSchema, "convention")
+	} else {
+		v.convention(root)
+	}
+	return v.diagnostics
+}
+
+// node is a parsed JSON value with the offset at which it starts
+type node struct {
+	path   string
+	offset int
+	// value is a string, json.Number, bool or nil for scalars, *object for objects and []*node for arrays
+	value any
+}
+
+type object struct {
+	keys   []string
+	fields map[string]*node
+}
+
+type validator struct {
+	file        string
+	data        []byte
+	diagnostics []Diagnostic
+	// invalid records the nodes which do not match the schema
+	invalid map[*node]bool
+	// errPath is the path being parsed when a syntax error occurred
+	errPath string
+	// schemaOnly rejects whatever the published schema rejects, as an editor would
+	schemaOnly bool
+}
+
+// parse reads the next value from decoder, recording the offset of each value so that diagnostics can point
+// at it
+func (v *validator) parse(decoder *json.Decoder, path string) (*node, error) {
+	v.errPath = path
+	n := &node{path: path, offset: v.valueStart(int(decoder.InputOffset()))}
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := &object{fields: make(map[string]*node)}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyToken.(string)
+			child, err := v.parse(decoder, path+"."+key)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := obj.fields[key]; ok {
+				v.add(child.path, child.offset, "duplicate field")
+			} else {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.fields[key] = child
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		n.value = obj
+	case json.Delim('['):
+		var items []*node
+		for decoder.More() {
+			child, err := v.parse(decoder, fmt.Sprintf("%s[%d]", path, len(items)))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, child)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		n.value = items
+	default:
+		n.value = token
+	}
+	return n, nil
+}
+
+// valueStart skips the whitespace and separators which precede a value at offset
+func (v *validator) valueStart(offset int) int {
+	for offset < len(v.data) && strings.ContainsRune(" \t\r\n:,", rune(v.data[offset])) {
+		offset++
+	}
+	return offset
+}
+
+func (v *validator) add(path string, offset int, format string, args ...any) {
+	line, column := 1, 1
+	for _, b := range v.data[:min(offset, len(v.data))] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		File: v.file, Path: path, Line: line, Column: column, Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) addAt(n *node, format string, args ...any) {
+	v.add(n.path, n.offset, format, args...)
+}
+
+func (v *validator) warnAt(n *node, format string, args ...any) {
+	v.add(n.path, n.offset, format, args...)
+	v.diagnostics[len(v.diagnostics)-1].Warning = true
+}
+
+// schema checks n, called name in messages, against s: the type of each value, unknown and missing fields and
+// enumerated names. It reports whether n is valid, recording nodes which are not.
+func (v *validator) schema(n *node, s *schema.Schema, name string) bool {
+	valid := true
+	switch s.Type {
+	case "object":
+		obj, ok := n.value.(*object)
+		if !ok {
+			v.addAt(n, "%s must be an object", name)
+			valid = false
+			break
+		}
+		for _, key := range obj.keys {
+			property, ok := s.Properties[key]
+			if !ok {
+				v.addAt(obj.fields[key], "unknown field %q", key)
+				valid = false
+			} else if !v.schema(obj.fields[key], property, key) {
+				valid = false
+			}
+		}
+		for _, key := range s.Required {
+			if _, ok := obj.fields[key]; !ok {
+				v.addAt(n, "%s must have a %s", name, key)
+				valid = false
+			}
+		}
+	case "array":
+		if n.value == nil {
+			// null is read as an empty array
+			break
+		}
+		items, ok := n.value.([]*node)
+		if !ok {
+			v.addAt(n, "%s must be %s", name, s.Describe())
+			valid = false
+			break
+		}
+		itemName := strings.ToLower(s.Items.Title)
+		for _, item := range items {
+			if s.Items.Type != "object" && !s.Items.Accepts(item.value) {
+				v.addAt(item, "%s must be %s", name, s.Describe())
+				v.invalid[item] = true
+				valid = false
+			} else if !v.schema(item, s.Items, cmp.Or(itemName, name)) {
+				valid = false
+			}
+		}
+	default:
+		if !s.Accepts(n.value) {
+			v.addAt(n, "%s must be %s", name, s.Describe())
+			valid = false
+		} else if len(s.Enum) > 0 && !slices.Contains(s.Enum, n.value.(string)) {
+			if name == "type" && n.value == model.Unspecified.String() && !v.schemaOnly {
+				// documents written before the schema listed the types may still use the zero value
+				v.warnAt(n, "type %s is deprecated: expected one of %s", n.value, strings.Join(s.Enum, ", "))
+				break
+			}
+			v.addAt(n, "%s %s is not valid: expected one of %s", name, n.value, strings.Join(s.Enum, ", "))
+			valid = false
+		}
+	}
+	if !valid {
+		v.invalid[n] = true
+	}
+	return valid
+}
+
+// str returns the named string field of obj, which is empty when missing
+func str(obj *object, name string) string {
+	if n, ok := obj.fields[name]; ok {
+		s, _ := n.value.(string)
+		return s
+	}
+	return ""
+}
+
+// strs returns the valid items of the named array field of obj
+func (v *validator) strs(obj *object, name string) []*node {
+	n, ok := obj.fields[name]
+	if !ok {
+		return nil
+	}
+	items, _ := n.value.([]*node)
+	var valid []*node
+	for _, item := range items {
+		if !v.invalid[item] {
+			valid = append(valid, item)
+		}
+	}
+	return valid
+}
+
+func (v *validator) convention(root *node) {
+	v.schema(root, ConventionSchema, "convention")
+	obj, ok := root.value.(*object)
+	if !ok {
+		return
+	}
+
+	if n, ok := obj.fields["name"]; !ok {
+		v.addAt(root, "convention must have a name")
+	} else if name, ok := n.value.(string); ok && strings.TrimSpace(name) == "" {
+		v.addAt(n, "convention must have a name")
+	}
+
+	extends := v.strs(obj, "extends")
+	for _, marker := range v.strs(obj, "detect") {
+		if err := pathmatch.Validate(marker.value.(string)); err != nil {
+			v.addAt(marker, "%v", err)
+		}
+	}
+
+	rulesNode, ok := obj.fields["rules"]
+	if !ok || rulesNode.value == nil {
+		if len(extends) == 0 {
+			v.addAt(root, "convention must have at least one rule")
+		}
+		return
+	}
+	items, ok := rulesNode.value.([]*node)
+	if !ok {
+		return
+	}
+	if len(items) == 0 && len(extends) == 0 {
+		v.addAt(rulesNode, "convention must have at least one rule")
+	}
+
+	// seen maps the key of each rule to its node, and ruleLevels each of those nodes to the rule's level
+	seen := make(map[string]*node)
+	ruleLevels := make(map[*node]model.StrictnessLevel)
+	ids := make(map[string]*node)
+	for _, item := range items {
+		if v.invalid[item] {
+			continue
+		}
+		rule, ok := v.rule(item)
+		if !ok {
+			continue
+		}
+
+		if rule.ID != "" {
+			if first, ok := ids[rule.ID]; ok {
+				v.addAt(item.value.(*object).fields["id"], "id %s is already used by rule %s", rule.ID, first.path)
+			} else {
+				ids[rule.ID] = item
+			}
+		}
+
+		// conditional rules have their own key, so they may set another level for the same item
+		key := ruleKey(rule)
+		if first, ok := seen[key]; ok {
+			if level := ruleLevels[first]; (rule.Level == model.Required && level == model.Prohibited) || (rule.Level == model.Prohibited && level == model.Required) {
+				v.addAt(item, "%s %s conflicts with %s rule %s", rule.Level, rule.Value, level, first.path)
+			} else {
+				v.addAt(item, "duplicate of rule %s", first.path)
+			}
+			continue
+		}
+		seen[key] = item
+		ruleLevels[item] = rule.Level
+	}
+}
+
+// rule checks a rule which matches the schema, returning it when it is valid
+func (v *validator) rule(n *node) (model.Rule, bool) {
+	obj := n.value.(*object)
+	before := len(v.diagnostics)
+
+	// the schema has checked the names
+	level, _ := model.ParseStrictnessLevel(str(obj, "level"))
+	ruleType, _ := model.ParseRuleType(str(obj, "type"))
+	rule := model.Rule{
+		Level:    level,
+		Type:     ruleType,
+		Value:    str(obj, "value"),
+		Contains: str(obj, "contains"),
+		Matches:  str(obj, "matches"),
+		Each:     str(obj, "each"),
+
+		ID:          str(obj, "id"),
+		Description: str(obj, "description"),
+		Rationale:   str(obj, "rationale"),
+		Remediation: str(obj, "remediation"),
+	}
+	excludes := v.strs(obj, "exclude")
+	for _, exclude := range excludes {
+		rule.Exclude = append(rule.Exclude, exclude.value.(string))
+	}
+	alternatives := v.strs(obj, "alternatives")
+	for _, alternative := range alternatives {
+		rule.Alternatives = append(rule.Alternatives, alternative.value.(string))
+		if strings.TrimSpace(alternative.value.(string)) == "" {
+			v.addAt(alternative, "empty alternative")
+		}
+	}
+
+	if n, ok := obj.fields["when"]; ok {
+		rule.When = v.condition(n)
+	}
+
+	if strings.TrimSpace(rule.Value) == "" {
+		v.addAt(obj.fields["value"], "empty value")
+		return rule, false
+	}
+
+	if _, ok := obj.fields["id"]; ok && !ruleID.MatchString(rule.ID) {
+		v.addAt(obj.fields["id"], "id %q must be letters, digits, '.', '_' or '-'", rule.ID)
+	}
+	if _, ok := obj.fields["rationale"]; ok {
+		if u, err := url.Parse(rule.Rationale); err != nil || u.Scheme == "" || u.Host == "" {
+			v.addAt(obj.fields["rationale"], "rationale must be a link, e.g. https://example.com/why")
+		}
+	}
+
+	switch rule.Type {
+	case model.Content:
+		if (rule.Contains == "") == (rule.Matches == "") {
+			v.addAt(n, "content rule must specify exactly one of contains or matches")
+		} else if rule.Matches != "" {
+			if _, err := regexp.Compile(rule.Matches); err != nil {
+				v.addAt(obj.fields["matches"], "invalid regular expression: %v", err)
+			}
+		}
+	case model.Pattern:
+		if err := pathmatch.Validate(rule.Value); err != nil {
+			v.addAt(obj.fields["value"], "%v", err)
+		}
+		for _, pattern := range slices.Concat(alternatives, excludes) {
+			if err := pathmatch.Validate(pattern.value.(string)); err != nil {
+				v.addAt(pattern, "%v", err)
+			}
+		}
+	}
+	if f, ok := obj.fields["alternatives"]; ok && rule.Type == model.Content {
+		v.addAt(f, "alternatives is only valid for directory, file and pattern rules")
+	}
+	if rule.Type != model.Content {
+		for _, name := range []string{"contains", "matches"} {
+			if f, ok := obj.fields[name]; ok {
+				v.addAt(f, "%s is only valid for content rules", name)
+			}
+		}
+	}
+	if rule.Type != model.Pattern {
+		for _, name := range []string{"exclude", "each"} {
+			if f, ok := obj.fields[name]; ok {
+				v.addAt(f, "%s is only valid for pattern rules", name)
+			}
+		}
+	}
+
+	return rule, len(v.diagnostics) == before
+}
+
+// condition checks the when clause of a rule which matches the schema
+func (v *validator) condition(n *node) *model.Condition {
+	obj := n.value.(*object)
+	conditionType, _ := model.ParseRuleType(str(obj, "type"))
+	condition := &model.Condition{
+		Type:     conditionType,
+		Value:    str(obj, "value"),
+		Contains: str(obj, "contains"),
+		Matches:  str(obj, "matches"),
+	}
+
+	if strings.TrimSpace(condition.Value) == "" {
+		v.addAt(obj.fields["value"], "empty value")
+		return condition
+	}
+	switch condition.Type {
+	case model.Content:
+		if (condition.Contains == "") == (condition.Matches == "") {
+			v.addAt(n, "content condition must specify exactly one of contains or matches")
+		} else if condition.Matches != "" {
+			if _, err := regexp.Compile(condition.Matches); err != nil {
+				v.addAt(obj.fields["matches"], "invalid regular expression: %v", err)
+			}
+		}
+	case model.Pattern:
+		if err := pathmatch.Validate(condition.Value); err != nil {
+			v.addAt(obj.fields["value"], "%v", err)
+		}
+	}
+	if condition.Type != model.Content {
+		for _, name := range []string{"contains", "matches"} {
+			if f, ok := obj.fields[name]; ok {
+				v.addAt(f, "%s is only valid for content conditions", name)
+			}
+		}
+	}
+	return condition
+}
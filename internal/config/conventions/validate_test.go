@@ -0,0 +1,277 @@
+package conventions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantNot bool
+	}{
+		{
+			name: "valid",
+			data: `{
+  "name": "Go",
+  "detect": ["go.mod"],
+  "rules": [
+    { "level": "required", "type": "file", "value": "go.mod" },
+    { "level": "required", "type": "content", "value": "README.md", "matches": "^# " },
+    { "level": "prohibited", "type": "content", "value": "README.md", "contains": "TODO" },
+    { "level": "prohibited", "type": "pattern", "value": "**/*.orig", "exclude": ["testdata/"] }
+  ]
+}`,
+		},
+		{
+			name: "extends without rules",
+			data: `{"name": "Our Go", "extends": ["Go"], "rules": null}`,
+		},
+		{
+			name: "unknown level and type",
+			data: `{
+  "name": "Go",
+  "rules": [
+    { "level": "mandatory", "type": "file", "value": "go.mod" },
+    { "level": "required", "type": "folder", "value": "docs" }
+  ]
+}`,
+			want: []string{
+				"4:16: $.rules[0].level: level mandatory is not valid: expected one of prohibited, optional, preferred, required",
+				"5:36: $.rules[1].type: type folder is not valid",
+			},
+		},
+		{
+			name: "empty value and missing fields",
+			data: `{"name": "Go", "rules": [{"level": "required", "type": "file", "value": " "}, {"type": "file", "value": "x"}]}`,
+			want: []string{
+				"1:73: $.rules[0].value: empty value",
+				"1:79: $.rules[1]: rule must have a level",
+			},
+		},
+		{
+			name: "duplicate and conflicting rules",
+			data: `{"name": "Go", "rules": [
+  {"level": "required", "type": "directory", "value": "src"},
+  {"level": "optional", "type": "directory", "value": "docs"},
+  {"level": "preferred", "type": "directory", "value": "docs"},
+  {"level": "prohibited", "type": "directory", "value": "src"}
+]}`,
+			want: []string{
+				"4:3: $.rules[2]: duplicate of rule $.rules[1]",
+				"5:3: $.rules[3]: prohibited src conflicts with required rule $.rules[0]",
+			},
+		},
+		{
+			name: "rules of different types on the same item conflict",
+			data: `{"name": "Go", "rules": [
+  {"level": "required", "type": "file", "value": "src"},
+  {"level": "prohibited", "type": "pattern", "value": "src"}
+]}`,
+			want: []string{"3:3: $.rules[1]: prohibited src conflicts with required rule $.rules[0]"},
+		},
+		{
+			name: "unknown fields and misplaced options",
+			data: `{"name": "Go", "rule": [], "rules": [{"level": "required", "type": "file", "value": "x", "each": "y", "matches": "("}]}`,
+			want: []string{
+				`1:24: $.rule: unknown field "rule"`,
+				"$.rules[0].each: each is only valid for pattern rules",
+				"$.rules[0].matches: matches is only valid for content rules",
+			},
+		},
+		{
+			name: "wrong types",
+			data: `{"name": 1, "detect": "go.mod", "rules": [{"level": "required", "type": "pattern", "value": "x", "exclude": [1]}, "README.md"]}`,
+			want: []string{
+				"1:10: $.name: name must be a string",
+				"1:23: $.detect: detect must be an array of strings",
+				"1:110: $.rules[0].exclude[0]: exclude must be an array of strings",
+				"1:115: $.rules[1]: rule must be an object",
+			},
+		},
+		{
+			name: "rule guidance",
+			data: `{"name": "Go", "rules": [
+  {"id": "license", "level": "required", "type": "file", "value": "LICENSE", "description": "Has a license", "rationale": "https://opensource.guide/legal/", "remediation": "ossify license MIT > LICENSE"},
+  {"id": "license", "level": "required", "type": "file", "value": "README.md"},
+  {"id": "no spaces", "level": "required", "type": "file", "value": "NOTICE", "rationale": "see the wiki"}
+]}`,
+			want: []string{
+				"3:10: $.rules[1].id: id license is already used by rule $.rules[0]",
+				`4:10: $.rules[2].id: id "no spaces" must be letters, digits, '.', '_' or '-'`,
+				"4:92: $.rules[2].rationale: rationale must be a link",
+			},
+		},
+		{
+			name: "alternatives",
+			data: `{"name": "Go", "rules": [
+  {"level": "required", "type": "file", "value": "README.md", "alternatives": ["README", " "]},
+  {"level": "prohibited", "type": "pattern", "value": "*.orig", "alternatives": ["[a-"]},
+  {"level": "required", "type": "content", "value": "README.md", "contains": "x", "alternatives": ["README"]}
+]}`,
+			want: []string{
+				"2:90: $.rules[0].alternatives[1]: empty alternative",
+				`3:82: $.rules[1].alternatives[0]: invalid pattern "[a-"`,
+				"4:99: $.rules[2].alternatives: alternatives is only valid for directory, file and pattern rules",
+			},
+		},
+		{
+			name: "conditions",
+			data: `{"name": "Go", "rules": [
+  {"level": "required", "type": "file", "value": ".dockerignore", "when": {"type": "file", "value": "Dockerfile"}},
+  {"level": "prohibited", "type": "file", "value": ".dockerignore", "when": {"type": "file", "value": "Containerfile"}},
+  {"level": "required", "type": "file", "value": "go.sum", "when": {"type": "content", "value": "go.mod"}},
+  {"level": "required", "type": "file", "value": "a", "when": {"type": "file", "value": "b", "contains": "c"}},
+  {"level": "required", "type": "file", "value": "d", "when": {"type": "socket", "value": "e"}}
+]}`,
+			want: []string{
+				"4:68: $.rules[2].when: content condition must specify exactly one of contains or matches",
+				"5:106: $.rules[3].when.contains: contains is only valid for content conditions",
+				"6:72: $.rules[4].when.type: type socket is not valid: expected one of directory, file, pattern, content",
+			},
+		},
+		{
+			name: "invalid expression",
+			data: `{"name": "Go", "rules": [{"level": "required", "type": "content", "value": "x", "matches": "("}]}`,
+			want: []string{"$.rules[0].matches: invalid regular expression"},
+		},
+		{
+			name: "unspecified type is deprecated",
+			data: `{"name": "Go", "rules": [{"level": "prohibited", "type": "unspecified", "value": ".bak"}]}`,
+			want: []string{"1:58: $.rules[0].type: warning: type unspecified is deprecated"},
+		},
+		{
+			name: "missing name and rules",
+			data: `{}`,
+			want: []string{"1:1: $: convention must have a name", "1:1: $: convention must have at least one rule"},
+		},
+		{
+			name: "syntax error",
+			data: "{\n  \"name\": \"Go\",\n  \"rules\": [,]\n}",
+			want: []string{"3:13: $.rules[0]: invalid character ','"},
+		},
+		{
+			name: "truncated",
+			data: `{"name": "Go", "rules": [`,
+			want: []string{"$.rules[0]: unexpected end of JSON input"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := Validate("go.json", []byte(tt.data))
+
+			got := make([]string, len(diagnostics))
+			for i, d := range diagnostics {
+				got[i] = d.String()
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validate() = %d diagnostics, want %d:\n%s", len(got), len(tt.want), strings.Join(got, "\n"))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(got[i], "go.json:") || !strings.Contains(got[i], want) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_Formats(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want []string
+	}{
+		{
+			name: "valid yaml",
+			file: "go.yaml",
+			data: "# our Go layout\nname: Go\nrules:\n  - { level: required, type: file, value: go.mod }\n",
+		},
+		{
+			name: "yaml positions",
+			file: "go.yaml",
+			data: "name: Go\nrules:\n  - level: required\n    type: file\n    value: go.mod\n  - level: mandatory\n    type: file\n    value: docs\n    colour: red\n",
+			want: []string{
+				"go.yaml:6:12: $.rules[1].level: level mandatory is not valid",
+				`go.yaml:9:13: $.rules[1].colour: unknown field "colour"`,
+			},
+		},
+		{
+			name: "yaml syntax error",
+			file: "go.yml",
+			data: "name: Go\nrules:\n  - level: required\n   type: file\n",
+			want: []string{`go.yml:2:1: $: yaml: line 2: did not find expected '-' indicator`},
+		},
+		{
+			name: "valid toml",
+			file: "go.toml",
+			data: "# our Go layout\nname = \"Go\"\n\n[[rules]]\nlevel = \"required\"\ntype = \"file\"\nvalue = \"go.mod\"\n",
+		},
+		{
+			name: "toml positions",
+			file: "go.toml",
+			data: "name = \"Go\"\ndetect = 1\n\n[[rules]]\nlevel = \"required\"\ntype = \"file\"\nvalue = \"go.mod\"\n\n[[rules]]\n  level = \"required\"\n  type = \"folder\"\n  value = \"docs\"\n",
+			want: []string{
+				"go.toml:2:1: $.detect: detect must be an array of strings",
+				"go.toml:11:3: $.rules[1].type: type folder is not valid",
+			},
+		},
+		{
+			name: "toml syntax error",
+			file: "go.toml",
+			data: "name = \"Go\"\n[[rules]]\nlevel = required\n",
+			want: []string{"go.toml:3:9: $: expected value but found \"required\" instead"},
+		},
+		{
+			name: "sniffed yaml",
+			file: "go",
+			data: "name: Go\nrules: []\n",
+			want: []string{"go:2:8: $.rules: convention must have at least one rule"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := Validate(tt.file, []byte(tt.data))
+
+			got := make([]string, len(diagnostics))
+			for i, d := range diagnostics {
+				got[i] = d.String()
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validate() = %d diagnostics, want %d:\n%s", len(got), len(tt.want), strings.Join(got, "\n"))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("diagnostic %d = %q, want it to start with %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	// the schema leaves the name and rules to the convention, e.g. for "ossify check --file"
+	if diagnostics := ValidateSchema("go.json", []byte(`{"rules": []}`)); len(diagnostics) > 0 {
+		t.Errorf("ValidateSchema() = %v, want no diagnostics", diagnostics)
+	}
+
+	diagnostics := ValidateSchema("go.yaml", []byte("rules:\n  - level: required\n    kind: file\n"))
+	want := []string{
+		"go.yaml:2:5: $.rules[0]: rule must have a type",
+		"go.yaml:2:5: $.rules[0]: rule must have a value",
+		`go.yaml:3:11: $.rules[0].kind: unknown field "kind"`,
+	}
+	if len(diagnostics) != len(want) {
+		t.Fatalf("ValidateSchema() = %v, want %v", diagnostics, want)
+	}
+	for i, d := range diagnostics {
+		if d.String() != want[i] {
+			t.Errorf("diagnostic %d = %q, want %q", i, d.String(), want[i])
+		}
+	}
+}
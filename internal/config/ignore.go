@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+// IgnoreFileName is the name of the file declaring a project's suppressions
+const IgnoreFileName = ".ossifyignore"
+
+// FindSuppressions loads the suppressions of the .ossifyignore found in dir or its parents up to topLevel,
+// as with FindProjectConfig. It returns no suppressions when there is no file.
+func FindSuppressions(dir, topLevel string) ([]model.Suppression, error) {
+	p := findUp(dir, topLevel, IgnoreFileName)
+	if p == "" {
+		return nil, nil
+	}
+	return LoadSuppressions(p)
+}
+
+// LoadSuppressions reads a suppression file
+func LoadSuppressions(path string) ([]model.Suppression, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return ParseSuppressions(f, path)
+}
+
+// ParseSuppressions parses suppressions, one per line, as pipe-delimited fields:
+//
+//	convention | value | reason | expires
+//
+// The convention may be "*" to match any convention. The reason is required, and the optional expiry is a
+// date (YYYY-MM-DD) after which the rule fails again. Blank lines and lines starting with "#" are ignored.
+// Every invalid line is reported, prefixed by source and its line number.
+func ParseSuppressions(r io.Reader, source string) ([]model.Suppression, error) {
+	var suppressions []model.Suppression
+	var errs []error
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		location := fmt.Sprintf("%s:%d", source, lineNumber)
+		s, err := parseSuppression(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", location, err))
+			continue
+		}
+		s.Source = location
+		suppressions = append(suppressions, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return suppressions, nil
+}
+
+func parseSuppression(line string) (model.Suppression, error) {
+	fields := strings.Split(line, "|")
+	if len(fields) < 3 || len(fields) > 4 {
+		return model.Suppression{}, errors.New("expected 'convention | value | reason | expires', with an optional expiry")
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	s := model.Suppression{Convention: fields[0], Value: fields[1], Reason: fields[2]}
+	switch {
+	case s.Convention == "":
+		return s, errors.New("convention is required (use * for any convention)")
+	case s.Value == "":
+		return s, errors.New("value is required")
+	case s.Reason == "":
+		return s, errors.New("a reason is required")
+	}
+
+	if len(fields) == 4 && fields[3] != "" {
+		expires, err := time.ParseInLocation(time.DateOnly, fields[3], time.Local)
+		if err != nil {
+			return s, fmt.Errorf("invalid expiry %q: expected YYYY-MM-DD", fields[3])
+		}
+		s.Expires = expires
+	}
+	return s, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSuppressions(t *testing.T) {
+	input := `# convention | value | reason | expires
+Go | src | generated SDK is published from src/ | 2026-12-31
+
+* | docs|docs live in the wiki
+`
+	got, err := ParseSuppressions(strings.NewReader(input), ".ossifyignore")
+	if err != nil {
+		t.Fatalf("ParseSuppressions() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ParseSuppressions() returned %d suppressions, want 2", len(got))
+	}
+
+	first := got[0]
+	if first.Convention != "Go" || first.Value != "src" || first.Reason != "generated SDK is published from src/" || first.Source != ".ossifyignore:2" {
+		t.Errorf("unexpected first suppression: %+v", first)
+	}
+	if want := time.Date(2026, 12, 31, 0, 0, 0, 0, time.Local); !first.Expires.Equal(want) {
+		t.Errorf("Expires = %v, want %v", first.Expires, want)
+	}
+	if second := got[1]; second.Convention != "*" || second.Value != "docs" || !second.Expires.IsZero() || second.Source != ".ossifyignore:4" {
+		t.Errorf("unexpected second suppression: %+v", second)
+	}
+}
+
+func TestParseSuppressions_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		errContains string
+	}{
+		{"missing reason", "Go | src", "expected 'convention | value | reason | expires'"},
+		{"empty reason", "Go | src | ", "a reason is required"},
+		{"empty convention", " | src | because", "convention is required"},
+		{"empty value", "Go | | because", "value is required"},
+		{"bad expiry", "Go | src | because | next year", `invalid expiry "next year"`},
+		{"too many fields", "Go | src | because | 2026-01-01 | extra", "expected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSuppressions(strings.NewReader("# header\n"+tt.line+"\n"), "ignore")
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) || !strings.Contains(err.Error(), "ignore:2") {
+				t.Errorf("ParseSuppressions() error = %v, want error at ignore:2 containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
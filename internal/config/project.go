@@ -0,0 +1,188 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/model"
+	"github.com/jimschubert/ossify/internal/pathmatch"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigNames are the file names of a project configuration, in order of precedence
+var ProjectConfigNames = []string{".ossify.json", ".ossify.yaml", ".ossify.yml"}
+
+// ProjectConfig is a repository's own configuration of the conventions it follows, discovered at the
+// repository root so that every run of `ossify check` applies the same rules.
+type ProjectConfig struct {
+	// Conventions names the conventions the project is checked against; when empty, conventions are detected
+	Conventions []string `json:"conventions,omitempty"`
+	// Rules are additional rules specific to the project
+	Rules []model.Rule `json:"rules,omitempty"`
+	// Overrides changes the level of convention rules, keyed by rule value
+	Overrides map[string]string `json:"overrides,omitempty"`
+	// Ignore lists the values of convention rules which do not apply to the project
+	Ignore []string `json:"ignore,omitempty"`
+	// Modules maps the module directories of a monorepo, relative to the configuration file and optionally as glob
+	// patterns, to the conventions a recursive check applies to them; an empty list detects the conventions
+	Modules map[string][]string `json:"modules,omitempty"`
+
+	// Path is the file the configuration was loaded from
+	Path string `json:"-"`
+}
+
+// FindProjectConfig looks for a project configuration in dir and each of its parents up to the root of the
+// git working tree containing dir (or only dir, outside a repository). It returns nil when there is none.
+func FindProjectConfig(dir, topLevel string) (*ProjectConfig, error) {
+	p := findUp(dir, topLevel, ProjectConfigNames...)
+	if p == "" {
+		return nil, nil
+	}
+	return LoadProjectConfig(p)
+}
+
+// findUp returns the path of the first of names found in dir or its parents up to topLevel, or an empty string.
+// Only dir is searched when topLevel is empty.
+func findUp(dir, topLevel string, names ...string) string {
+	dir = filepath.Clean(dir)
+	for {
+		for _, name := range names {
+			p := filepath.Join(dir, name)
+			if _, err := os.Stat(p); err == nil {
+				return p
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if topLevel == "" || dir == filepath.Clean(topLevel) || parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig reads a JSON or YAML (by extension) project configuration
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// YAML is converted to JSON so rules are validated by the same unmarshalling as convention files
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if doc == nil {
+			doc = map[string]any{}
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var c ProjectConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	c.Path = path
+	return &c, nil
+}
+
+func (c *ProjectConfig) validate() error {
+	var errs []error
+	for value, level := range c.Overrides {
+		if _, err := model.ParseStrictnessLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("override for %s: %w", value, err))
+		}
+	}
+	for pattern := range c.Modules {
+		if err := pathmatch.Validate(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("module %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ModuleDirs returns the absolute paths of the directories matched by the keys of Modules, with the names of the
+// conventions configured for each. A directory matched by several keys is checked against all of their conventions,
+// in the order of the sorted keys.
+func (c *ProjectConfig) ModuleDirs() (map[string][]string, error) {
+	root := filepath.Dir(c.Path)
+	dirs := make(map[string][]string)
+	add := func(rel string, names []string) {
+		dir := filepath.Join(root, filepath.FromSlash(rel))
+		if _, ok := dirs[dir]; !ok {
+			dirs[dir] = []string{}
+		}
+		for _, name := range names {
+			if !slices.Contains(dirs[dir], name) {
+				dirs[dir] = append(dirs[dir], name)
+			}
+		}
+	}
+
+	// keys are visited in order so that a directory matched by several keys lists its conventions consistently
+	patterns := make([]string, 0, len(c.Modules))
+	for pattern := range c.Modules {
+		patterns = append(patterns, pattern)
+	}
+	slices.Sort(patterns)
+
+	for _, pattern := range patterns {
+		names := c.Modules[pattern]
+		if path.Clean(filepath.ToSlash(pattern)) == "." {
+			add(".", names)
+			continue
+		}
+		matches, err := pathmatch.Glob(root, strings.TrimSuffix(pattern, "/")+"/")
+		if err != nil {
+			return nil, fmt.Errorf("%s: module %s: %w", c.Path, pattern, err)
+		}
+		for _, rel := range matches {
+			add(rel, names)
+		}
+	}
+	return dirs, nil
+}
+
+// Apply returns a copy of convention with the ignored rules removed and the overridden levels applied
+func (c *ProjectConfig) Apply(convention model.Convention) model.Convention {
+	rules := make([]model.Rule, 0, len(convention.Rules))
+	for _, rule := range convention.Rules {
+		if slices.Contains(c.Ignore, rule.Value) {
+			continue
+		}
+		if name, ok := c.Overrides[rule.Value]; ok {
+			// validated when loaded
+			rule.Level, _ = model.ParseStrictnessLevel(name)
+		}
+		rules = append(rules, rule)
+	}
+	convention.Rules = rules
+	return convention
+}
+
+// Convention returns the project's own rules as a convention named for the configuration file, or nil
+// when the project has no rules of its own
+func (c *ProjectConfig) Convention() *model.Convention {
+	if len(c.Rules) == 0 {
+		return nil
+	}
+	return &model.Convention{Name: filepath.Base(c.Path), Rules: c.Rules}
+}
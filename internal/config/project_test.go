@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+func writeProjectFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return p
+}
+
+func TestLoadProjectConfig(t *testing.T) {
+	want := ProjectConfig{
+		Conventions: []string{"Go"},
+		Rules:       []model.Rule{{Level: model.Required, Type: model.File, Value: "CODEOWNERS"}},
+		Overrides:   map[string]string{"docs": "preferred"},
+		Ignore:      []string{"src"},
+	}
+
+	tests := []struct {
+		name        string
+		file        string
+		content     string
+		errContains string
+	}{
+		{
+			name: "json",
+			file: ".ossify.json",
+			content: `{
+  "conventions": ["Go"],
+  "rules": [{ "level": "required", "type": "file", "value": "CODEOWNERS" }],
+  "overrides": { "docs": "preferred" },
+  "ignore": ["src"]
+}`,
+		},
+		{
+			name: "yaml",
+			file: ".ossify.yaml",
+			content: `# project conventions
+conventions: [Go]
+rules:
+  - level: required
+    type: file
+    value: CODEOWNERS
+overrides:
+  docs: preferred
+ignore:
+  - src
+`,
+		},
+		{name: "unknown level", file: ".ossify.json", content: `{"overrides": {"docs": "mandatory"}}`, errContains: "level mandatory is not valid"},
+		{name: "invalid rule", file: ".ossify.yml", content: "rules:\n  - level: required\n    type: folder\n    value: docs\n", errContains: "type folder is not valid"},
+		{name: "unknown field", file: ".ossify.json", content: `{"convention": "Go"}`, errContains: "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := writeProjectFile(t, t.TempDir(), tt.file, tt.content)
+			got, err := LoadProjectConfig(p)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("LoadProjectConfig() error = %v, want error containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadProjectConfig() error = %v", err)
+			}
+
+			expected := want
+			expected.Path = p
+			if !reflect.DeepEqual(*got, expected) {
+				t.Errorf("LoadProjectConfig() = %+v, want %+v", *got, expected)
+			}
+		})
+	}
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, ".ossify.yaml", "conventions: [Go]\n")
+	service := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(service, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	if got, err := FindProjectConfig(service, root); err != nil || got == nil || got.Path != filepath.Join(root, ".ossify.yaml") {
+		t.Errorf("FindProjectConfig() within repository = %+v, %v", got, err)
+	}
+	if got, err := FindProjectConfig(service, ""); err != nil || got != nil {
+		t.Errorf("FindProjectConfig() outside a repository = %+v, %v, want nil", got, err)
+	}
+
+	writeProjectFile(t, service, ".ossify.json", `{"conventions": ["Node.js"]}`)
+	if got, err := FindProjectConfig(service, root); err != nil || got == nil || got.Conventions[0] != "Node.js" {
+		t.Errorf("FindProjectConfig() nearest = %+v, %v", got, err)
+	}
+}
+
+func TestProjectConfig_Apply(t *testing.T) {
+	c := &ProjectConfig{
+		Overrides: map[string]string{"docs": "optional"},
+		Ignore:    []string{"src"},
+		Path:      "/repo/.ossify.json",
+	}
+	convention := model.Convention{Name: "Go", Rules: []model.Rule{
+		{Level: model.Required, Type: model.Directory, Value: "docs"},
+		{Level: model.Prohibited, Type: model.Directory, Value: "src"},
+		{Level: model.Required, Type: model.File, Value: "LICENSE"},
+	}}
+
+	got := c.Apply(convention)
+	want := []model.Rule{
+		{Level: model.Optional, Type: model.Directory, Value: "docs"},
+		{Level: model.Required, Type: model.File, Value: "LICENSE"},
+	}
+	if !reflect.DeepEqual(got.Rules, want) {
+		t.Errorf("Apply() = %+v, want %+v", got.Rules, want)
+	}
+	if convention.Rules[0].Level != model.Required {
+		t.Errorf("Apply() modified the original convention")
+	}
+	if c.Convention() != nil {
+		t.Errorf("Convention() without rules should be nil")
+	}
+}
+
+func TestProjectConfig_ModuleDirs(t *testing.T) {
+	root := t.TempDir()
+	p := writeProjectFile(t, root, ".ossify.yaml", `modules:
+  .: [Standard Distribution]
+  services/*: [Go]
+  services/api/: [Docker]
+  web: []
+`)
+	for _, dir := range []string{"services/api", "services/worker", "web"} {
+		writeProjectFile(t, root, filepath.Join(dir, "README.md"), "")
+	}
+	writeProjectFile(t, root, "services/notes.txt", "")
+
+	c, err := LoadProjectConfig(p)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error = %v", err)
+	}
+	got, err := c.ModuleDirs()
+	if err != nil {
+		t.Fatalf("ModuleDirs() error = %v", err)
+	}
+
+	want := map[string][]string{
+		root:                                   {"Standard Distribution"},
+		filepath.Join(root, "services", "api"): {"Go", "Docker"},
+		filepath.Join(root, "services", "worker"): {"Go"},
+		filepath.Join(root, "web"):                {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ModuleDirs() = %v, want %v", got, want)
+	}
+
+	if _, err := LoadProjectConfig(writeProjectFile(t, t.TempDir(), ".ossify.json", `{"modules": {"services/[": ["Go"]}}`)); err == nil {
+		t.Errorf("expected an error for an invalid module pattern")
+	}
+}
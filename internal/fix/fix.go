@@ -0,0 +1,405 @@
+// Package fix plans and applies changes which bring a directory in line with a convention.
+package fix
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/jimschubert/ossify/internal/licenses"
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+//go:embed templates
+var templateContent embed.FS
+
+// ActionKind is the type of change an Action makes
+type ActionKind int
+
+const (
+	CreateDirectory ActionKind = iota
+	CreateFile
+	Delete
+	Move
+)
+
+var actionKindNames = []string{
+	CreateDirectory: "create directory",
+	CreateFile:      "create file",
+	Delete:          "delete",
+	Move:            "move",
+}
+
+// ProhibitedMode determines what happens to prohibited items which exist
+type ProhibitedMode string
+
+const (
+	// Ask defers the decision for each prohibited item to Options.Ask
+	Ask         ProhibitedMode = "ask"
+	DeleteItems ProhibitedMode = "delete"
+	MoveItems   ProhibitedMode = "move"
+	SkipItems   ProhibitedMode = "skip"
+)
+
+// gitKeepName is the conventional empty file which allows git to track an otherwise empty directory
+const gitKeepName = ".gitkeep"
+
+// movedIgnore is written to the .gitignore of Options.MoveTo, so that moved items are neither committed nor
+// matched again by pattern rules
+const movedIgnore = "*\n"
+
+// ParseProhibitedMode validates a prohibited mode name
+func ParseProhibitedMode(name string) (ProhibitedMode, error) {
+	switch mode := ProhibitedMode(strings.ToLower(name)); mode {
+	case Ask, DeleteItems, MoveItems, SkipItems:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown prohibited mode %q: expected one of ask, delete, move, skip", name)
+	}
+}
+
+// Action is a single planned change, relative to the checked directory
+type Action struct {
+	Kind ActionKind
+	// Path is the item created, deleted or moved
+	Path string
+	// Target is the destination of a Move
+	Target string
+	// Content is the content of a created file
+	Content []byte
+	// Source describes where created content comes from, e.g. "license MIT" or "template README.md"
+	Source string
+	Rule   model.Rule
+}
+
+// String describes the action for display in a change set
+func (a Action) String() string {
+	switch a.Kind {
+	case Move:
+		return fmt.Sprintf("%s %s -> %s", actionKindNames[a.Kind], a.Path, a.Target)
+	case CreateFile:
+		if a.Source != "" {
+			return fmt.Sprintf("%s %s (from %s)", actionKindNames[a.Kind], a.Path, a.Source)
+		}
+		return fmt.Sprintf("%s %s (empty)", actionKindNames[a.Kind], a.Path)
+	default:
+		return fmt.Sprintf("%s %s", actionKindNames[a.Kind], a.Path)
+	}
+}
+
+// Options configures how a change set is planned
+type Options struct {
+	// GitKeep adds an empty .gitkeep to created directories so they can be committed
+	GitKeep bool
+	// License is the license id used to generate a missing LICENSE; when empty, LICENSE is not generated
+	License string
+	// LicenseLocation is the directory of user-defined license templates
+	LicenseLocation string
+	// Values are substituted into generated license text and templates
+	Values licenses.Values
+	// Prohibited determines what happens to prohibited items which exist
+	Prohibited ProhibitedMode
+	// MoveTo is the directory, relative to the checked directory, into which prohibited items are moved; a
+	// .gitignore is created in it so that the moved items are not checked again
+	MoveTo string
+	// Ask is consulted for each prohibited item when Prohibited is Ask, and must return DeleteItems, MoveItems or SkipItems
+	Ask func(path string) ProhibitedMode
+}
+
+// Skipped is a failed rule which the planner could not fix, with the reason
+type Skipped struct {
+	Rule   model.Rule
+	Reason string
+}
+
+// licenseFileNames are the file names generated from the license corpus
+var licenseFileNames = map[string]bool{"LICENSE": true, "LICENSE.md": true, "LICENSE.txt": true, "COPYING": true}
+
+// Plan builds the change set which fixes the failed rules of results, which must all be results for root.
+// Missing required or preferred directories and files are created and existing prohibited items are deleted
+// or moved according to opts. Failures which cannot be fixed, such as content rules or paths outside of root,
+// are returned as skipped.
+func Plan(root string, results []*model.CheckResult, opts Options) ([]Action, []Skipped, error) {
+	var actions []Action
+	var skipped []Skipped
+	planned := map[string]bool{}
+
+	add := func(action Action) {
+		if planned[action.Path] {
+			return
+		}
+		planned[action.Path] = true
+		actions = append(actions, action)
+	}
+
+	for _, result := range results {
+		for _, r := range result.Results {
+			status := r.Status()
+			if status != model.StatusFail && status != model.StatusWarn {
+				continue
+			}
+
+			rule := r.Rule
+			if rule.Level == model.Prohibited {
+				for _, p := range prohibitedPaths(root, r) {
+					if !filepath.IsLocal(p) {
+						skipped = append(skipped, Skipped{Rule: rule, Reason: fmt.Sprintf("%s is outside of the checked directory", p)})
+						continue
+					}
+					action, ok := planProhibited(p, rule, opts)
+					if !ok {
+						skipped = append(skipped, Skipped{Rule: rule, Reason: fmt.Sprintf("left %s in place", p)})
+						continue
+					}
+					if action.Kind == Move {
+						if !filepath.IsLocal(action.Target) {
+							skipped = append(skipped, Skipped{Rule: rule, Reason: fmt.Sprintf("%s is outside of the checked directory", action.Target)})
+							continue
+						}
+						if ignore := filepath.Join(opts.MoveTo, ".gitignore"); !exists(root, ignore) {
+							add(Action{Kind: CreateFile, Path: ignore, Content: []byte(movedIgnore), Source: "ignore pattern *", Rule: rule})
+						}
+					}
+					add(action)
+				}
+				continue
+			}
+
+			if !filepath.IsLocal(rule.Value) {
+				skipped = append(skipped, Skipped{Rule: rule, Reason: "path is outside of the checked directory"})
+				continue
+			}
+
+			switch rule.Type {
+			case model.Directory:
+				if exists(root, rule.Value) {
+					skipped = append(skipped, Skipped{Rule: rule, Reason: "a file exists at this path"})
+					continue
+				}
+				add(Action{Kind: CreateDirectory, Path: rule.Value, Rule: rule})
+				if opts.GitKeep {
+					add(Action{Kind: CreateFile, Path: filepath.Join(rule.Value, gitKeepName), Rule: rule})
+				}
+			case model.File:
+				if exists(root, rule.Value) {
+					skipped = append(skipped, Skipped{Rule: rule, Reason: "a directory exists at this path"})
+					continue
+				}
+				action, err := planFile(rule.Value, rule, opts)
+				if err != nil {
+					skipped = append(skipped, Skipped{Rule: rule, Reason: err.Error()})
+					continue
+				}
+				add(action)
+			case model.Pattern:
+				if rule.Each == "" || len(r.Paths) == 0 {
+					skipped = append(skipped, Skipped{Rule: rule, Reason: "pattern rules cannot be fixed automatically"})
+					continue
+				}
+				// create the missing Each item in every match which lacks it
+				for _, match := range r.Paths {
+					item := filepath.Join(filepath.FromSlash(match), rule.Each)
+					if strings.HasSuffix(rule.Each, "/") {
+						add(Action{Kind: CreateDirectory, Path: item, Rule: rule})
+						continue
+					}
+					action, err := planFile(item, rule, opts)
+					if err != nil {
+						skipped = append(skipped, Skipped{Rule: rule, Reason: err.Error()})
+						continue
+					}
+					add(action)
+				}
+			default:
+				skipped = append(skipped, Skipped{Rule: rule, Reason: fmt.Sprintf("%s rules cannot be fixed automatically", rule.Type)})
+			}
+		}
+	}
+
+	return actions, skipped, nil
+}
+
+// Apply performs actions within root, stopping at the first error. Actions on paths outside of root are errors.
+func Apply(root string, actions []Action) error {
+	for _, action := range actions {
+		if !filepath.IsLocal(action.Path) || (action.Kind == Move && !filepath.IsLocal(action.Target)) {
+			return fmt.Errorf("%s: path is outside of %s", action, root)
+		}
+		target := filepath.Join(root, action.Path)
+		var err error
+		switch action.Kind {
+		case CreateDirectory:
+			err = os.MkdirAll(target, 0755)
+		case CreateFile:
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+				err = writeNewFile(target, action.Content)
+			}
+		case Delete:
+			err = os.RemoveAll(target)
+		case Move:
+			destination := filepath.Join(root, action.Target)
+			if _, statErr := os.Stat(destination); statErr == nil {
+				err = fmt.Errorf("destination %s already exists", action.Target)
+			} else if err = os.MkdirAll(filepath.Dir(destination), 0755); err == nil {
+				err = os.Rename(target, destination)
+			}
+		default:
+			err = fmt.Errorf("unknown action %d", action.Kind)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", action, err)
+		}
+	}
+	return nil
+}
+
+// PrintPlan writes a change set and the failures which could not be fixed to w
+func PrintPlan(w io.Writer, actions []Action, skipped []Skipped) {
+	if len(actions) == 0 {
+		_, _ = fmt.Fprintln(w, "No changes to make.")
+	} else {
+		_, _ = fmt.Fprintln(w, "Changes:")
+		for _, action := range actions {
+			_, _ = fmt.Fprintf(w, "  + %s\n", action)
+		}
+	}
+
+	if len(skipped) > 0 {
+		_, _ = fmt.Fprintln(w, "Not fixed:")
+		for _, s := range skipped {
+			_, _ = fmt.Fprintf(w, "  - %s %s %s: %s\n", s.Rule.Level, s.Rule.Type, s.Rule.Value, s.Reason)
+		}
+	}
+}
+
+func planProhibited(path string, rule model.Rule, opts Options) (Action, bool) {
+	mode := opts.Prohibited
+	if mode == Ask || mode == "" {
+		mode = SkipItems
+		if opts.Ask != nil {
+			mode = opts.Ask(path)
+		}
+	}
+
+	switch mode {
+	case DeleteItems:
+		return Action{Kind: Delete, Path: path, Rule: rule}, true
+	case MoveItems:
+		return Action{Kind: Move, Path: path, Target: filepath.Join(opts.MoveTo, path), Rule: rule}, true
+	default:
+		return Action{}, false
+	}
+}
+
+// prohibitedPaths lists the existing items, relative to root, which violate a prohibited rule. A directory or
+// file rule is violated only by the values recorded as failing, and only by an item of the rule's type.
+func prohibitedPaths(root string, r model.RuleResult) []string {
+	rule := r.Rule
+	switch rule.Type {
+	case model.Directory, model.File:
+		values := r.Paths
+		if len(values) == 0 {
+			values = rule.Values()
+		}
+		var paths []string
+		for _, value := range values {
+			if info, err := os.Stat(filepath.Join(root, value)); err == nil && info.IsDir() == (rule.Type == model.Directory) {
+				paths = append(paths, value)
+			}
+		}
+		return paths
+	case model.Pattern:
+		paths := make([]string, 0, len(r.Paths))
+		for _, p := range r.Paths {
+			paths = append(paths, filepath.Join(filepath.FromSlash(p), rule.Each))
+		}
+		return paths
+	default:
+		return nil
+	}
+}
+
+func planFile(path string, rule model.Rule, opts Options) (Action, error) {
+	name := filepath.Base(path)
+	action := Action{Kind: CreateFile, Path: path, Rule: rule}
+
+	if licenseFileNames[name] {
+		if opts.License == "" {
+			return Action{}, errors.New("no license selected (use --license)")
+		}
+		text, err := licenses.RenderLicenseText(opts.License, opts.LicenseLocation, opts.Values, false)
+		if err != nil {
+			return Action{}, fmt.Errorf("rendering license %s: %w", opts.License, err)
+		}
+		action.Content = []byte(text)
+		action.Source = "license " + opts.License
+		return action, nil
+	}
+
+	content, ok, err := renderTemplate(name, opts)
+	if err != nil {
+		return Action{}, err
+	}
+	if ok {
+		action.Content = content
+		action.Source = "template " + name
+	}
+	return action, nil
+}
+
+// renderTemplate renders the built-in template for a file name, reporting false if there is none
+func renderTemplate(name string, opts Options) ([]byte, bool, error) {
+	b, err := templateContent.ReadFile("templates/" + name + ".tmpl")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	tmpl, err := template.New(name).Parse(string(b))
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing template %s: %w", name, err)
+	}
+
+	project := opts.Values.Project
+	if project == "" {
+		project = "this project"
+	}
+	data := struct {
+		Project     string
+		Description string
+		Holder      string
+		License     string
+	}{project, opts.Values.Description, opts.Values.Holder, opts.License}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, false, fmt.Errorf("rendering template %s: %w", name, err)
+	}
+	return buf.Bytes(), true, nil
+}
+
+func exists(root, path string) bool {
+	_, err := os.Stat(filepath.Join(root, path))
+	return err == nil
+}
+
+// writeNewFile writes content to path, failing rather than overwriting an existing file
+func writeNewFile(path string, content []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,269 @@
+package fix
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jimschubert/ossify/internal/licenses"
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+func evaluate(t *testing.T, dir string, rules ...model.Rule) []*model.CheckResult {
+	t.Helper()
+	convention := model.Convention{Name: "Test", Rules: rules}
+	result, err := convention.Evaluate(dir)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	return []*model.CheckResult{result}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(t *testing.T, dir string)
+		rules       []model.Rule
+		opts        Options
+		wantActions []string
+		wantSkipped int
+	}{
+		{
+			name: "creates missing directories with gitkeep",
+			rules: []model.Rule{
+				{Level: model.Required, Type: model.Directory, Value: "cmd"},
+				{Level: model.Preferred, Type: model.Directory, Value: "docs"},
+				{Level: model.Optional, Type: model.Directory, Value: "examples"},
+			},
+			opts: Options{GitKeep: true},
+			wantActions: []string{
+				"create directory cmd",
+				"create file cmd/.gitkeep (empty)",
+				"create directory docs",
+				"create file docs/.gitkeep (empty)",
+			},
+		},
+		{
+			name: "generates files from license and templates",
+			rules: []model.Rule{
+				{Level: model.Required, Type: model.File, Value: "LICENSE"},
+				{Level: model.Required, Type: model.File, Value: "README.md"},
+				{Level: model.Preferred, Type: model.File, Value: "CONTRIBUTING.md"},
+				{Level: model.Required, Type: model.File, Value: "go.mod"},
+			},
+			opts: Options{License: "MIT", Values: licenses.Values{Holder: "Jim", Year: "2024", Project: "ossify"}},
+			wantActions: []string{
+				"create file LICENSE (from license MIT)",
+				"create file README.md (from template README.md)",
+				"create file CONTRIBUTING.md (from template CONTRIBUTING.md)",
+				"create file go.mod (empty)",
+			},
+		},
+		{
+			name:        "skips LICENSE without a license id",
+			rules:       []model.Rule{{Level: model.Required, Type: model.File, Value: "LICENSE"}},
+			wantSkipped: 1,
+		},
+		{
+			name: "deletes and moves prohibited items",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "src", "main.go"), "package main")
+				writeFile(t, filepath.Join(dir, "a.orig"), "")
+				writeFile(t, filepath.Join(dir, "b.orig"), "")
+			},
+			rules: []model.Rule{
+				{Level: model.Prohibited, Type: model.Directory, Value: "src"},
+				{Level: model.Prohibited, Type: model.Pattern, Value: "*.orig"},
+			},
+			opts: Options{Prohibited: Ask, MoveTo: "removed", Ask: func(path string) ProhibitedMode {
+				if path == "src" {
+					return MoveItems
+				}
+				if path == "a.orig" {
+					return DeleteItems
+				}
+				return SkipItems
+			}},
+			wantActions: []string{
+				"create file removed/.gitignore (from ignore pattern *)",
+				"move src -> removed/src",
+				"delete a.orig",
+			},
+			wantSkipped: 1,
+		},
+		{
+			name: "creates missing items for each pattern match",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "cmd", "app", "main.go"), "package main")
+				writeFile(t, filepath.Join(dir, "cmd", "tool", "tool.go"), "package main")
+				writeFile(t, filepath.Join(dir, "src", "a", "b.orig"), "")
+			},
+			rules: []model.Rule{
+				{Level: model.Required, Type: model.Pattern, Value: "cmd/*/", Each: "main.go"},
+				{Level: model.Prohibited, Type: model.Pattern, Value: "**/*.orig"},
+			},
+			opts: Options{Prohibited: DeleteItems},
+			wantActions: []string{
+				"create file cmd/tool/main.go (empty)",
+				"delete src/a/b.orig",
+			},
+		},
+		{
+			name: "does not fix content rules or paths outside the directory",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "README.md"), "hello")
+			},
+			rules: []model.Rule{
+				{Level: model.Required, Type: model.Content, Value: "README.md", Contains: "Usage"},
+				{Level: model.Required, Type: model.File, Value: "../outside"},
+			},
+			wantSkipped: 2,
+		},
+		{
+			name: "deletes only alternatives of the prohibited type",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "build", "out.bin"), "")
+				writeFile(t, filepath.Join(dir, "dist"), "not a directory")
+				writeFile(t, filepath.Join(dir, "notes"), "")
+				writeFile(t, filepath.Join(dir, "tmp", "keep"), "")
+			},
+			rules: []model.Rule{
+				{Level: model.Prohibited, Type: model.Directory, Value: "build", Alternatives: []string{"dist"}},
+				{Level: model.Prohibited, Type: model.File, Value: "tmp", Alternatives: []string{"notes"}},
+			},
+			opts: Options{Prohibited: DeleteItems},
+			wantActions: []string{
+				"delete build",
+				"delete notes",
+			},
+		},
+		{
+			name: "does not delete or move items outside the directory",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "..", "precious", "data"), "keep")
+				writeFile(t, filepath.Join(dir, "vendor", "a.go"), "package a")
+			},
+			rules: []model.Rule{
+				{Level: model.Prohibited, Type: model.Directory, Value: "../precious"},
+				{Level: model.Prohibited, Type: model.Directory, Value: "vendor"},
+			},
+			opts:        Options{Prohibited: MoveItems, MoveTo: "../removed"},
+			wantSkipped: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.setup != nil {
+				tt.setup(t, dir)
+			}
+
+			actions, skipped, err := Plan(dir, evaluate(t, dir, tt.rules...), tt.opts)
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+
+			got := make([]string, len(actions))
+			for i, a := range actions {
+				got[i] = a.String()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.wantActions, "\n") {
+				t.Errorf("Plan() actions =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.wantActions, "\n"))
+			}
+			if len(skipped) != tt.wantSkipped {
+				t.Errorf("Plan() skipped = %+v, want %d", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "src", "main.go"), "package main")
+	writeFile(t, filepath.Join(dir, "junk.orig"), "")
+
+	rules := []model.Rule{
+		{Level: model.Required, Type: model.Directory, Value: "cmd"},
+		{Level: model.Required, Type: model.File, Value: "LICENSE"},
+		{Level: model.Required, Type: model.File, Value: "README.md"},
+		{Level: model.Prohibited, Type: model.Directory, Value: "src"},
+		{Level: model.Prohibited, Type: model.Pattern, Value: "**/*.orig"},
+	}
+	opts := Options{
+		GitKeep:    true,
+		License:    "MIT",
+		Values:     licenses.Values{Holder: "Jim Schubert", Year: "2024", Project: "ossify", Description: "Tooling for OSS."},
+		Prohibited: MoveItems,
+		MoveTo:     "removed",
+	}
+
+	actions, _, err := Plan(dir, evaluate(t, dir, rules...), opts)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if err := Apply(dir, actions); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	result := evaluate(t, dir, rules...)[0]
+	if result.HasFailures() {
+		t.Errorf("expected no failures after fix, got %+v", result.Results)
+	}
+
+	for _, p := range []string{"cmd/.gitkeep", "removed/src/main.go", "removed/junk.orig", "removed/.gitignore"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); err != nil {
+			t.Errorf("expected %s to exist: %v", p, err)
+		}
+	}
+
+	license, _ := os.ReadFile(filepath.Join(dir, "LICENSE"))
+	if !strings.Contains(string(license), "Jim Schubert") {
+		t.Errorf("LICENSE does not contain the holder:\n%s", license)
+	}
+	readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+	if !strings.HasPrefix(string(readme), "# ossify\n\nTooling for OSS.\n") || !strings.Contains(string(readme), "MIT license") {
+		t.Errorf("unexpected README.md:\n%s", readme)
+	}
+
+	// nothing outside of the directory is touched
+	for _, action := range []Action{{Kind: Delete, Path: "../x"}, {Kind: Move, Path: "cmd", Target: "../cmd"}} {
+		if err := Apply(dir, []Action{action}); err == nil {
+			t.Errorf("Apply(%s) should fail", action)
+		}
+	}
+
+	// applying again must not overwrite files which now exist
+	if err := Apply(dir, actions[:1]); err != nil {
+		t.Errorf("re-creating a directory should succeed, got %v", err)
+	}
+	for _, a := range actions {
+		if a.Path == "LICENSE" {
+			if err := Apply(dir, []Action{a}); err == nil {
+				t.Errorf("expected an error when LICENSE already exists")
+			}
+		}
+	}
+}
+
+func TestParseProhibitedMode(t *testing.T) {
+	for _, name := range []string{"ask", "DELETE", "move", "skip"} {
+		if _, err := ParseProhibitedMode(name); err != nil {
+			t.Errorf("ParseProhibitedMode(%q) error = %v", name, err)
+		}
+	}
+	if _, err := ParseProhibitedMode("archive"); err == nil {
+		t.Errorf("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,212 @@
+// Package fleet checks many repositories at once and aggregates their results, so that a platform team can
+// audit every checked-out repository in one run.
+package fleet
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+// Repository is the outcome of checking one repository
+type Repository struct {
+	Directory string
+	Results   []*model.CheckResult
+	// Err is set when the repository could not be checked
+	Err error
+}
+
+// CheckFunc checks a single repository directory
+type CheckFunc func(dir string) ([]*model.CheckResult, error)
+
+// Failed reports whether the repository could not be checked or has failures
+func (r *Repository) Failed() bool {
+	if r.Err != nil {
+		return true
+	}
+	for _, result := range r.Results {
+		if result.HasFailures() {
+			return true
+		}
+	}
+	return false
+}
+
+// Count sums the outcome counts of the repository's results
+func (r *Repository) Count() (failed, warnings int) {
+	for _, result := range r.Results {
+		failed += result.FailCount
+		warnings += result.WarnCount
+	}
+	return failed, warnings
+}
+
+// Directories resolves the repositories named by source: the path of a manifest file listing one directory per
+// line, or otherwise a glob pattern (supporting "**") matching directories
+func Directories(source string) ([]string, error) {
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		return ReadManifest(source)
+	}
+
+	matches, err := doublestar.FilepathGlob(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository pattern %q: %w", source, err)
+	}
+
+	var dirs []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			dirs = append(dirs, match)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no repositories match %q", source)
+	}
+	return dirs, nil
+}
+
+// ReadManifest reads a manifest listing one repository directory per line. Blank lines and lines starting with
+// "#" are skipped, and relative directories are relative to the manifest.
+func ReadManifest(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var dirs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(filepath.Dir(path), line)
+		}
+		dirs = append(dirs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("manifest %s lists no repositories", path)
+	}
+	return dirs, nil
+}
+
+// Check runs check for each directory with at most jobs concurrent workers. Repositories are returned in the
+// order of dirs.
+func Check(dirs []string, jobs int, check CheckFunc) []Repository {
+	repos := make([]Repository, len(dirs))
+	jobs = max(1, min(jobs, len(dirs)))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results, err := check(dirs[i])
+				repos[i] = Repository{Directory: dirs[i], Results: results, Err: err}
+			}
+		}()
+	}
+
+	for i := range dirs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return repos
+}
+
+// RuleStat is how many of the checked repositories pass a rule of a convention. A rule passes unless it failed
+// or warned; suppressed failures pass. Repositories in which the rule was skipped, as its condition did not hold,
+// are not counted.
+type RuleStat struct {
+	Convention string
+	Rule       model.Rule
+	Checked    int
+	Passed     int
+}
+
+// PassRate is the fraction of checked repositories passing the rule
+func (s RuleStat) PassRate() float64 {
+	if s.Checked == 0 {
+		return 1
+	}
+	return float64(s.Passed) / float64(s.Checked)
+}
+
+// RuleStats returns the statistics of every rule checked in repos, lowest pass rate first
+func RuleStats(repos []Repository) []RuleStat {
+	var stats []RuleStat
+	index := make(map[string]int)
+	for _, repo := range repos {
+		for _, result := range repo.Results {
+			for _, r := range result.Results {
+				if r.Skipped {
+					continue
+				}
+				key := strings.Join([]string{strings.ToLower(result.Convention), r.Rule.Level.String(), r.Rule.Type.String(),
+					r.Rule.Value, r.Rule.Contains, r.Rule.Matches, r.Rule.Each, strings.Join(r.Rule.Exclude, "\x01"),
+					strings.Join(r.Rule.Alternatives, "\x01")}, "\x00")
+				if r.Rule.When != nil {
+					key += "\x00when " + r.Rule.When.String()
+				}
+				i, ok := index[key]
+				if !ok {
+					i = len(stats)
+					index[key] = i
+					stats = append(stats, RuleStat{Convention: result.Convention, Rule: r.Rule})
+				}
+				stats[i].Checked++
+				if status := r.Status(); status != model.StatusFail && status != model.StatusWarn {
+					stats[i].Passed++
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(stats, func(a, b RuleStat) int {
+		return cmp.Compare(a.PassRate(), b.PassRate())
+	})
+	return stats
+}
+
+// WorstOffenders returns up to limit failing repositories, those which could not be checked first and then by
+// the most failures and warnings
+func WorstOffenders(repos []Repository, limit int) []Repository {
+	var failing []Repository
+	for _, repo := range repos {
+		if repo.Failed() {
+			failing = append(failing, repo)
+		}
+	}
+
+	slices.SortStableFunc(failing, func(a, b Repository) int {
+		if (a.Err != nil) != (b.Err != nil) {
+			if a.Err != nil {
+				return -1
+			}
+			return 1
+		}
+		aFailed, aWarnings := a.Count()
+		bFailed, bWarnings := b.Count()
+		return cmp.Or(cmp.Compare(bFailed, aFailed), cmp.Compare(bWarnings, aWarnings))
+	})
+	if len(failing) > limit {
+		failing = failing[:limit]
+	}
+	return failing
+}
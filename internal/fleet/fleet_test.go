@@ -0,0 +1,155 @@
+package fleet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+func checkResult(results ...model.RuleResult) *model.CheckResult {
+	result := &model.CheckResult{Convention: "Go", Results: results}
+	result.Recount()
+	return result
+}
+
+var (
+	license = model.Rule{Level: model.Required, Type: model.File, Value: "LICENSE"}
+	docs    = model.Rule{Level: model.Preferred, Type: model.Directory, Value: "docs"}
+)
+
+func TestDirectories(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a", "b", "c"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	manifest := filepath.Join(root, "repos.txt")
+	if err := os.WriteFile(manifest, []byte("# checked out by CI\na\n\n  b  \n/abs/c\n"), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	got, err := Directories(manifest)
+	if err != nil {
+		t.Fatalf("Directories() manifest error = %v", err)
+	}
+	want := []string{filepath.Join(root, "a"), filepath.Join(root, "b"), "/abs/c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Directories() manifest = %v, want %v", got, want)
+	}
+
+	got, err = Directories(filepath.Join(root, "*"))
+	if err != nil {
+		t.Fatalf("Directories() glob error = %v", err)
+	}
+	want = []string{filepath.Join(root, "a"), filepath.Join(root, "b"), filepath.Join(root, "c")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Directories() glob = %v, want %v", got, want)
+	}
+
+	if _, err := Directories(filepath.Join(root, "missing-*")); err == nil {
+		t.Errorf("expected an error when no repository matches")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dirs := []string{"a", "b", "c", "d", "e"}
+	var running, peak atomic.Int32
+
+	repos := Check(dirs, 2, func(dir string) ([]*model.CheckResult, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		if dir == "c" {
+			return nil, errors.New("not a repository")
+		}
+		return []*model.CheckResult{checkResult(model.RuleResult{Rule: license, Passed: dir != "b"})}, nil
+	})
+
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent checks, got %d", peak.Load())
+	}
+	for i, repo := range repos {
+		if repo.Directory != dirs[i] {
+			t.Errorf("repository %d = %s, want %s", i, repo.Directory, dirs[i])
+		}
+	}
+	if !repos[1].Failed() || !repos[2].Failed() || repos[0].Failed() {
+		t.Errorf("unexpected failures: %+v", repos)
+	}
+}
+
+func TestRuleStats_WorstOffenders(t *testing.T) {
+	repos := []Repository{
+		{Directory: "clean", Results: []*model.CheckResult{checkResult(
+			model.RuleResult{Rule: license, Passed: true},
+			model.RuleResult{Rule: docs, Passed: true},
+		)}},
+		{Directory: "warned", Results: []*model.CheckResult{checkResult(
+			model.RuleResult{Rule: license, Passed: true},
+			model.RuleResult{Rule: docs},
+		)}},
+		{Directory: "failed", Results: []*model.CheckResult{checkResult(
+			model.RuleResult{Rule: license},
+			model.RuleResult{Rule: docs},
+		)}},
+		{Directory: "broken", Err: errors.New("not a directory")},
+	}
+
+	stats := RuleStats(repos)
+	if len(stats) != 2 || stats[0].Rule.Value != "docs" || stats[0].Passed != 1 || stats[0].Checked != 3 {
+		t.Errorf("RuleStats() = %+v, want docs first with 1/3 passing", stats)
+	}
+
+	// rules which differ only by their condition are counted separately
+	conditional := docs
+	conditional.When = &model.Condition{Type: model.File, Value: "mkdocs.yml"}
+	stats = RuleStats([]Repository{{Directory: "conditional", Results: []*model.CheckResult{checkResult(
+		model.RuleResult{Rule: docs, Passed: true},
+		model.RuleResult{Rule: conditional},
+	)}}})
+	if len(stats) != 2 || stats[0].Rule.When == nil || stats[0].Checked != 1 {
+		t.Errorf("RuleStats() = %+v, want the conditional rule counted separately", stats)
+	}
+
+	// skipped rules count toward neither checked nor passed, and rules differing by excludes are counted separately
+	pattern := model.Rule{Level: model.Prohibited, Type: model.Pattern, Value: "**/*.orig"}
+	excluding := pattern
+	excluding.Exclude = []string{"testdata/**"}
+	stats = RuleStats([]Repository{
+		{Directory: "skipped", Results: []*model.CheckResult{checkResult(
+			model.RuleResult{Rule: conditional, Skipped: true},
+			model.RuleResult{Rule: pattern},
+			model.RuleResult{Rule: excluding, Passed: true},
+		)}},
+		{Directory: "applies", Results: []*model.CheckResult{checkResult(
+			model.RuleResult{Rule: conditional},
+		)}},
+	})
+	if len(stats) != 3 {
+		t.Fatalf("RuleStats() = %+v, want 3 rules", stats)
+	}
+	for _, stat := range stats {
+		if stat.Rule.When != nil && (stat.Checked != 1 || stat.Passed != 0) {
+			t.Errorf("conditional rule %d/%d, want 0/1 as it was skipped once", stat.Passed, stat.Checked)
+		}
+	}
+
+	var got []string
+	for _, repo := range WorstOffenders(repos, 2) {
+		got = append(got, repo.Directory)
+	}
+	if want := []string{"broken", "failed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WorstOffenders() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package licenses
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// shingleSize is the number of consecutive words compared between texts.
+// Three words is long enough to distinguish license families while tolerating small edits.
+const shingleSize = 3
+
+// Match is a candidate license for a detected text.
+type Match struct {
+	ID         string
+	Confidence float64
+	Custom     bool
+}
+
+var (
+	copyrightLinePattern = regexp.MustCompile(`(?im)^[ \t]*(copyright|\(c\)|©).*$`)
+	nonWordPattern       = regexp.MustCompile(`[^a-z0-9]+`)
+	spellingVariants     = strings.NewReplacer("licence", "license", "whilst", "while", "per cent", "percent")
+)
+
+// Normalize reduces license text to a canonical form for comparison: copyright lines and
+// template placeholders are removed, text is lowercased, and punctuation and whitespace collapse to single spaces.
+func Normalize(text string) string {
+	text = copyrightLinePattern.ReplaceAllString(text, " ")
+	text = placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if _, ok := lookupPlaceholder(match); ok {
+			return " "
+		}
+		return match
+	})
+	text = legacyYearPattern.ReplaceAllString(text, " ")
+	text = strings.ToLower(text)
+	text = spellingVariants.Replace(text)
+	text = nonWordPattern.ReplaceAllString(text, " ")
+	return strings.TrimSpace(text)
+}
+
+// Detect compares text against the embedded license corpus and any user-defined templates in
+// customTemplateLocation, returning all candidates ordered by descending confidence (0 to 1).
+// User-defined templates replace embedded templates with the same id.
+func Detect(text string, customTemplateLocation string) ([]Match, error) {
+	corpus, err := loadCorpus(customTemplateLocation)
+	if err != nil {
+		return nil, err
+	}
+
+	target := shingles(Normalize(text))
+	matches := make([]Match, 0, len(corpus))
+	for id, template := range corpus {
+		matches = append(matches, Match{
+			ID:         id,
+			Confidence: similarity(target, shingles(Normalize(template.text))),
+			Custom:     template.custom,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Confidence == matches[j].Confidence {
+			return matches[i].ID < matches[j].ID
+		}
+		return matches[i].Confidence > matches[j].Confidence
+	})
+
+	return matches, nil
+}
+
+type corpusEntry struct {
+	text   string
+	custom bool
+}
+
+func loadCorpus(customTemplateLocation string) (map[string]corpusEntry, error) {
+	corpus := map[string]corpusEntry{}
+
+	embedded, err := fs.ReadDir(licenseContent, "data/texts/plain")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range embedded {
+		if entry.IsDir() {
+			continue
+		}
+		b, err := licenseContent.ReadFile(path.Join("data/texts/plain", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		corpus[entry.Name()] = corpusEntry{text: string(b)}
+	}
+
+	if customTemplateLocation == "" {
+		return corpus, nil
+	}
+
+	custom, err := os.ReadDir(customTemplateLocation)
+	if os.IsNotExist(err) {
+		return corpus, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range custom {
+		if entry.IsDir() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(customTemplateLocation, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		corpus[entry.Name()] = corpusEntry{text: string(b), custom: true}
+	}
+
+	return corpus, nil
+}
+
+func shingles(normalized string) map[string]struct{} {
+	words := strings.Fields(normalized)
+	result := make(map[string]struct{})
+	if len(words) < shingleSize {
+		if len(words) > 0 {
+			result[strings.Join(words, " ")] = struct{}{}
+		}
+		return result
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		result[strings.Join(words[i:i+shingleSize], " ")] = struct{}{}
+	}
+	return result
+}
+
+// similarity is the Sørensen–Dice coefficient of two shingle sets.
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	small, large := a, b
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	shared := 0
+	for s := range small {
+		if _, ok := large[s]; ok {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
@@ -0,0 +1,79 @@
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"collapses whitespace", "Permission  is\n\thereby   granted", "permission is hereby granted"},
+		{"strips punctuation", `"AS IS", WITHOUT WARRANTY;`, "as is without warranty"},
+		{"removes copyright lines", "Copyright (c) 2024 Jane Doe\nAll rights reserved.", "all rights reserved"},
+		{"removes placeholders", "Neither the name of <OWNER> nor", "neither the name of nor"},
+		{"normalizes spelling", "This Licence applies", "this license applies"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.input); got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		id     string
+		values Values
+	}{
+		{"MIT", Values{Holder: "Jane Doe", Year: "2024"}},
+		{"BSD-2", Values{Holder: "Acme, Inc.", Year: "2010-2024"}},
+		{"BSD-3", Values{Holder: "Acme, Inc.", Year: "2010-2024"}},
+		{"Apache-2.0", Values{}},
+		{"GPL-3.0", Values{Holder: "Jane Doe", Project: "ossify"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			text, err := RenderLicenseText(tt.id, "", tt.values, false)
+			if err != nil {
+				t.Fatalf("RenderLicenseText() error = %v", err)
+			}
+
+			matches, err := Detect(text, "")
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if matches[0].ID != tt.id {
+				t.Errorf("Detect() best match = %s (%.2f), want %s", matches[0].ID, matches[0].Confidence, tt.id)
+			}
+			if matches[0].Confidence < 0.95 {
+				t.Errorf("Detect() confidence = %.2f, want >= 0.95", matches[0].Confidence)
+			}
+		})
+	}
+}
+
+func TestDetect_CustomTemplate(t *testing.T) {
+	customDir := t.TempDir()
+	custom := "The Acme Internal License\n\nYou may read this software but you may not run it, " +
+		"copy it, or share it with any person outside of Acme without written approval.\n"
+	if err := os.WriteFile(filepath.Join(customDir, "Acme-1.0"), []byte(custom), 0644); err != nil {
+		t.Fatalf("failed to write custom template: %v", err)
+	}
+
+	matches, err := Detect("Copyright 2024 Acme\n\n"+custom, customDir)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if matches[0].ID != "Acme-1.0" || !matches[0].Custom {
+		t.Errorf("Detect() best match = %+v, want custom Acme-1.0", matches[0])
+	}
+}
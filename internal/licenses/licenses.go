@@ -26,7 +26,9 @@ func Load() (*model.Licenses, error) {
 	return licenses, err
 }
 
-func PrintLicenseText(id string, customTemplateLocation string) error {
+// LoadLicenseText returns the raw text for the license id, preferring a user-defined
+// template in customTemplateLocation over the embedded corpus.
+func LoadLicenseText(id string, customTemplateLocation string) (string, error) {
 	location := path.Join("data/texts/plain/", id)
 	customLocation := path.Join(customTemplateLocation, id)
 
@@ -35,18 +37,44 @@ func PrintLicenseText(id string, customTemplateLocation string) error {
 	if _, customErr := os.Stat(customLocation); os.IsNotExist(customErr) {
 		embeddedContent, err := licenseContent.ReadFile(location)
 		if err != nil {
-			return err
+			return "", err
 		}
 		b = embeddedContent
 	} else {
 		customContent, err := os.ReadFile(customLocation)
 		if err != nil {
-			return err
+			return "", err
 		}
 		b = customContent
 	}
 
-	str := string(b)
+	return string(b), nil
+}
+
+// RenderLicenseText loads the license text for id and substitutes placeholders with values.
+// When strict is true, an error wrapping ErrUnfilledPlaceholders is returned if any known placeholder remains.
+func RenderLicenseText(id string, customTemplateLocation string, values Values, strict bool) (string, error) {
+	text, err := LoadLicenseText(id, customTemplateLocation)
+	if err != nil {
+		return "", err
+	}
+
+	text = values.Substitute(text)
+	if strict {
+		if err := values.Check(text); err != nil {
+			return "", err
+		}
+	}
+
+	return text, nil
+}
+
+func PrintLicenseText(id string, customTemplateLocation string) error {
+	str, err := LoadLicenseText(id, customTemplateLocation)
+	if err != nil {
+		return err
+	}
+
 	fmt.Println(str)
 
 	return nil
@@ -0,0 +1,99 @@
+package licenses
+
+import (
+	"testing"
+)
+
+func TestLoad_Metadata(t *testing.T) {
+	all, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for _, license := range *all {
+		if len(license.Keywords) == 0 {
+			t.Errorf("license %s has no keywords", license.Id)
+		}
+		if len(license.Links) == 0 {
+			t.Errorf("license %s has no links", license.Id)
+		}
+	}
+}
+
+func TestLicenses_FindByKeyword(t *testing.T) {
+	all, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// keywords documented in the README and the license command's --keyword help
+	keywords := []string{
+		"copyleft",
+		"discouraged",
+		"international",
+		"miscellaneous",
+		"non-reusable",
+		"obsolete",
+		"osi-approved",
+		"permissive",
+		"popular",
+		"redundant",
+		"retired",
+		"special-purpose",
+	}
+
+	for _, keyword := range keywords {
+		t.Run(keyword, func(t *testing.T) {
+			if got := all.FindByKeyword(keyword); len(*got) == 0 {
+				t.Errorf("FindByKeyword(%q) returned no licenses", keyword)
+			}
+		})
+	}
+}
+
+func TestLicenses_FindByKeyword_Popular(t *testing.T) {
+	all, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []string{"Apache-2.0", "BSD-2", "BSD-3", "GPL-3.0", "MIT", "MPL-2.0"}
+	popular := all.FindByKeyword("popular")
+	for _, id := range want {
+		if popular.FindById(id) == nil {
+			t.Errorf("expected %s to be a popular license", id)
+		}
+	}
+}
+
+func TestLicenses_Search_SPDX(t *testing.T) {
+	all, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		term   string
+		wantID string
+	}{
+		{"BSD-3-Clause", "BSD-3"},
+		{"UPL-1.0", "UPL"},
+		{"SimPL-2.0", "Simple-2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			results := all.Search(tt.term)
+			found := false
+			for _, license := range *results {
+				if license.Id == tt.wantID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("Search(%q) did not return %s", tt.term, tt.wantID)
+			}
+		})
+	}
+}
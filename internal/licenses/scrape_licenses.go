@@ -22,6 +22,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -38,10 +39,43 @@ import (
 
 const licensesURL = "https://s3.amazonaws.com/api.opensource.org/licenses/licenses.json"
 
-// License represents a license entry from licenses.json
+// License represents a license entry from licenses.json. It mirrors model.License so that
+// upstream metadata is validated on download; unknown fields are preserved by writing the
+// upstream document rather than re-marshaling this type.
 type License struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	Identifiers  []Identifier `json:"identifiers"`
+	Keywords     []string     `json:"keywords"`
+	Links        []Link       `json:"links"`
+	OtherNames   []OtherName  `json:"other_names"`
+	SupersededBy *string      `json:"superseded_by"`
+	Text         []Text       `json:"text"`
+}
+
+// Identifier is a license identifier within a scheme such as SPDX, DEP5 or Trove
+type Identifier struct {
+	Identifier string `json:"identifier"`
+	Scheme     string `json:"scheme"`
+}
+
+// Link is a reference to further information about a license
+type Link struct {
+	Note *string `json:"note"`
+	URL  string  `json:"url"`
+}
+
+// OtherName is an alternative name by which a license is known
+type OtherName struct {
+	Name string  `json:"name"`
+	Note *string `json:"note"`
+}
+
+// Text is a location of the license text in a given media type
+type Text struct {
+	MediaType string `json:"media_type"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
 }
 
 func main() {
@@ -212,18 +246,25 @@ func updateLicensesJSON(client *http.Client, destPath string, verbose bool) (boo
 		fmt.Printf("Downloaded %d licenses from upstream\n", len(licenses))
 	}
 
+	// Metadata drives keyword lookup, SPDX matching and license details, so refuse an
+	// upstream document which has lost it rather than silently overwriting our copy.
+	if err := validateMetadata(licenses); err != nil {
+		return false, fmt.Errorf("incomplete metadata from upstream: %w", err)
+	}
+
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
 		return false, fmt.Errorf("creating directory: %w", err)
 	}
 
-	// Write the file with pretty formatting
-	prettyJSON, err := json.MarshalIndent(licenses, "", "  ")
-	if err != nil {
+	// Write the upstream document with pretty formatting. Indenting the raw body (rather than
+	// re-marshaling our struct) keeps every field upstream provides.
+	var prettyJSON bytes.Buffer
+	if err := json.Indent(&prettyJSON, body, "", "  "); err != nil {
 		return false, fmt.Errorf("formatting JSON: %w", err)
 	}
 
-	if err := os.WriteFile(destPath, prettyJSON, 0644); err != nil {
+	if err := os.WriteFile(destPath, prettyJSON.Bytes(), 0644); err != nil {
 		return false, fmt.Errorf("writing file: %w", err)
 	}
 
@@ -240,6 +281,22 @@ func updateLicensesJSON(client *http.Client, destPath string, verbose bool) (boo
 	return true, nil
 }
 
+// validateMetadata ensures every license carries the metadata used by keyword and identifier lookups.
+func validateMetadata(licenses []License) error {
+	for _, lic := range licenses {
+		if lic.ID == "" || lic.Name == "" {
+			return fmt.Errorf("license entry is missing id or name")
+		}
+		if len(lic.Keywords) == 0 {
+			return fmt.Errorf("%s: no keywords", lic.ID)
+		}
+		if len(lic.Identifiers) == 0 && len(lic.Links) == 0 {
+			return fmt.Errorf("%s: no identifiers or links", lic.ID)
+		}
+	}
+	return nil
+}
+
 func scrapeLicense(client *http.Client, id string) (string, error) {
 	// Try new URL format first: /license/mit
 	urls := []string{
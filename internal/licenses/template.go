@@ -0,0 +1,219 @@
+package licenses
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnfilledPlaceholders is returned by Values.Check when a license text still contains known placeholders.
+var ErrUnfilledPlaceholders = errors.New("license text contains unfilled placeholders")
+
+// Values holds the data substituted into license placeholders such as <YEAR> or [name of copyright owner].
+type Values struct {
+	Holder       string
+	Year         string
+	Project      string
+	Description  string
+	Organization string
+	URL          string
+	Email        string
+}
+
+type placeholderVar int
+
+const (
+	varHolder placeholderVar = iota
+	varYear
+	varProject
+	varProjectSummary
+	varOrganization
+	varContact
+	varCopyright
+)
+
+// placeholderPattern matches the placeholder styles used across data/texts/plain: <...>, [...], {...} and the
+// shell-like $VAR.
+var placeholderPattern = regexp.MustCompile(`<([^<>\n]{1,80})>|\[([^\[\]\n]{1,80})\]|\{([^{}\n]{1,40})\}|\$[A-Z][A-Z_]{2,39}\b`)
+
+// legacyYearPattern matches the bare "19yy" used by older GNU license texts.
+var legacyYearPattern = regexp.MustCompile(`\b19yy\b`)
+
+// knownPlaceholders maps the normalized placeholder text found in the license corpus to the value it represents.
+var knownPlaceholders = map[string]placeholderVar{
+	// copyright holder
+	"owner":                   varHolder,
+	"author":                  varHolder,
+	"name of author":          varHolder,
+	"copyright holder":        varHolder,
+	"copyright holders":       varHolder,
+	"name of copyright owner": varHolder,
+	"owner organization name": varHolder,
+
+	"additional copyright holder": varHolder,
+
+	"enter full name of initial contributor":          varHolder,
+	"insert the name of the initial contributor here": varHolder,
+
+	// organization, falling back to the holder
+	"organization":              varOrganization,
+	"organisation":              varOrganization,
+	"name of institution":       varOrganization,
+	"name of development group": varOrganization,
+
+	// year or year range
+	"year":  varYear,
+	"yyyy":  varYear,
+	"dates": varYear,
+
+	"date-of-software": varYear,
+
+	// a whole copyright line
+	"copyright notice":      varCopyright,
+	"copyright information": varCopyright,
+
+	// project
+	"program": varProject,
+
+	"one line to give the program's name and a brief idea of what it does.": varProjectSummary,
+	"one line to give the library's name and a brief idea of what it does.": varProjectSummary,
+
+	// contact details
+	"url|email":                             varContact,
+	"url for development group/institution": varContact,
+
+	"insert initial contributor's designated web site here":    varContact,
+	"enter url for designated web site of initial contributor": varContact,
+}
+
+// ParseYear normalizes a year or year range. An open range such as "2019-" is closed with the current year,
+// and a range whose start equals the current year collapses to a single year.
+func ParseYear(value string, now time.Time) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return strconv.Itoa(now.Year()), nil
+	}
+
+	start, end, isRange := strings.Cut(value, "-")
+	start = strings.TrimSpace(start)
+	end = strings.TrimSpace(end)
+	if _, err := strconv.Atoi(start); err != nil || len(start) != 4 {
+		return "", fmt.Errorf("invalid year %q", value)
+	}
+	if !isRange {
+		return start, nil
+	}
+
+	if end == "" {
+		end = strconv.Itoa(now.Year())
+	}
+	if _, err := strconv.Atoi(end); err != nil || len(end) != 4 {
+		return "", fmt.Errorf("invalid year range %q", value)
+	}
+	if end < start {
+		return "", fmt.Errorf("invalid year range %q: end precedes start", value)
+	}
+	if end == start {
+		return start, nil
+	}
+	return start + "-" + end, nil
+}
+
+// Substitute replaces every known placeholder in text for which a value is available.
+// Placeholders without a value are left untouched so that Check can report them.
+func (v Values) Substitute(text string) string {
+	text = placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		variable, ok := lookupPlaceholder(match)
+		if !ok {
+			return match
+		}
+		if value := v.value(variable); value != "" {
+			return value
+		}
+		return match
+	})
+
+	if v.Year != "" {
+		text = legacyYearPattern.ReplaceAllString(text, v.Year)
+	}
+
+	return text
+}
+
+// Check returns ErrUnfilledPlaceholders, listing each distinct placeholder left in text.
+func (v Values) Check(text string) error {
+	remaining := Unfilled(text)
+	if len(remaining) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnfilledPlaceholders, strings.Join(remaining, ", "))
+}
+
+// Unfilled lists the distinct known placeholders present in text, in sorted order.
+func Unfilled(text string) []string {
+	seen := map[string]bool{}
+	for _, match := range placeholderPattern.FindAllString(text, -1) {
+		if _, ok := lookupPlaceholder(match); ok {
+			seen[match] = true
+		}
+	}
+	for _, match := range legacyYearPattern.FindAllString(text, -1) {
+		seen[match] = true
+	}
+
+	result := make([]string, 0, len(seen))
+	for match := range seen {
+		result = append(result, match)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func lookupPlaceholder(match string) (placeholderVar, bool) {
+	inner := strings.TrimPrefix(match, "$")
+	if inner == match {
+		inner = match[1 : len(match)-1]
+	}
+	// a bracketed $variable, e.g. [$date-of-software]
+	inner = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(inner)), "$")
+	inner = strings.TrimSuffix(inner, ":")
+	variable, ok := knownPlaceholders[inner]
+	return variable, ok
+}
+
+func (v Values) value(variable placeholderVar) string {
+	switch variable {
+	case varHolder:
+		return v.Holder
+	case varYear:
+		return v.Year
+	case varProject:
+		return v.Project
+	case varProjectSummary:
+		if v.Project != "" && v.Description != "" {
+			return fmt.Sprintf("%s - %s", v.Project, v.Description)
+		}
+		return v.Project
+	case varOrganization:
+		if v.Organization != "" {
+			return v.Organization
+		}
+		return v.Holder
+	case varContact:
+		if v.URL != "" {
+			return v.URL
+		}
+		return v.Email
+	case varCopyright:
+		if v.Holder == "" {
+			return ""
+		}
+		return strings.Join(strings.Fields(fmt.Sprintf("Copyright %s %s", v.Year, v.Holder)), " ")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,142 @@
+package licenses
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseYear(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"empty defaults to current year", "", "2026", false},
+		{"single year", "2024", "2024", false},
+		{"closed range", "2019-2024", "2019-2024", false},
+		{"open range", "2019-", "2019-2026", false},
+		{"range collapses", "2026-2026", "2026", false},
+		{"not a year", "last year", "", true},
+		{"short year", "24", "", true},
+		{"reversed range", "2024-2019", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseYear(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseYear() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseYear() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValues_Substitute(t *testing.T) {
+	values := Values{
+		Holder:      "Jane Doe",
+		Year:        "2019-2024",
+		Project:     "ossify",
+		Description: "project conventions",
+		Email:       "jane@example.com",
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"angle brackets", "Copyright <YEAR> <COPYRIGHT HOLDER>", "Copyright 2019-2024 Jane Doe"},
+		{"lowercase angle brackets", "Copyright (C) <year>  <name of author>", "Copyright (C) 2019-2024  Jane Doe"},
+		{"square brackets", "Copyright [yyyy] [name of copyright owner]", "Copyright 2019-2024 Jane Doe"},
+		{"curly braces", `Copyright " {YEAR} United States`, `Copyright " 2019-2024 United States`},
+		{"legacy year", "Copyright (C) 19yy  <name of author>", "Copyright (C) 2019-2024  Jane Doe"},
+		{"organization falls back to holder", "Neither the name of <ORGANIZATION> nor", "Neither the name of Jane Doe nor"},
+		{"project summary", "<one line to give the program's name and a brief idea of what it does.>", "ossify - project conventions"},
+		{"contact falls back to email", "(<URL|email>)", "(jane@example.com)"},
+		{"shell variables", "Copyright (c) $YEAR, $ORGANIZATION; $ORGANISATION", "Copyright (c) 2019-2024, Jane Doe; Jane Doe"},
+		{"bracketed variable", `"Copyright © [$date-of-software] World Wide`, `"Copyright © 2019-2024 World Wide`},
+		{"copyright line", "<copyright notice>\n<Copyright Information>", "Copyright 2019-2024 Jane Doe\nCopyright 2019-2024 Jane Doe"},
+		{"additional holder", "Copyright (c) <dates>, <additional Copyright Holder>", "Copyright (c) 2019-2024, Jane Doe"},
+		{"unknown variables are untouched", "$MirOS: src/share/misc/licence.template", "$MirOS: src/share/misc/licence.template"},
+		{"urls are untouched", "<http://www.gnu.org/licenses/>", "<http://www.gnu.org/licenses/>"},
+		{"unknown brackets are untouched", "[required/not required]", "[required/not required]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := values.Substitute(tt.input); got != tt.want {
+				t.Errorf("Substitute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValues_Check(t *testing.T) {
+	values := Values{Year: "2024"}
+	text := values.Substitute("Copyright <YEAR> <COPYRIGHT HOLDER>, <OWNER>")
+
+	err := values.Check(text)
+	if !errors.Is(err, ErrUnfilledPlaceholders) {
+		t.Fatalf("Check() error = %v, want ErrUnfilledPlaceholders", err)
+	}
+	if !strings.Contains(err.Error(), "<COPYRIGHT HOLDER>, <OWNER>") {
+		t.Errorf("Check() error = %v, want both unfilled placeholders listed", err)
+	}
+
+	values.Holder = "Jane Doe"
+	if err := values.Check(values.Substitute("Copyright <YEAR> <COPYRIGHT HOLDER>, <OWNER>")); err != nil {
+		t.Errorf("Check() error = %v, want nil", err)
+	}
+}
+
+func TestRenderLicenseText_Strict(t *testing.T) {
+	values := Values{Holder: "Jane Doe", Year: "2024"}
+	got, err := RenderLicenseText("MIT", t.TempDir(), values, true)
+	if err != nil {
+		t.Fatalf("RenderLicenseText() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "Copyright 2024 Jane Doe") {
+		t.Errorf("RenderLicenseText() = %q, want substituted copyright line", strings.SplitN(got, "\n", 2)[0])
+	}
+
+	_, err = RenderLicenseText("MIT", t.TempDir(), Values{Year: "2024"}, true)
+	if !errors.Is(err, ErrUnfilledPlaceholders) {
+		t.Errorf("RenderLicenseText() error = %v, want ErrUnfilledPlaceholders", err)
+	}
+}
+
+func TestRenderLicenseText_Corpus(t *testing.T) {
+	values := Values{
+		Holder:       "Jane Doe",
+		Year:         "2024",
+		Project:      "ossify",
+		Description:  "project conventions",
+		Organization: "Example Org",
+		URL:          "https://example.com",
+		Email:        "jane@example.com",
+	}
+
+	entries, err := licenseContent.ReadDir("data/texts/plain")
+	if err != nil {
+		t.Fatalf("reading license texts: %v", err)
+	}
+	for _, entry := range entries {
+		t.Run(entry.Name(), func(t *testing.T) {
+			text, err := RenderLicenseText(entry.Name(), t.TempDir(), values, false)
+			if err != nil {
+				t.Fatalf("RenderLicenseText() error = %v", err)
+			}
+			if remaining := Unfilled(text); len(remaining) > 0 {
+				t.Errorf("Unfilled() = %v, want none", remaining)
+			}
+		})
+	}
+}
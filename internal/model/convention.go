@@ -1,12 +1,17 @@
 package model
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/jimschubert/ossify/internal/pathmatch"
 	"github.com/jimschubert/ossify/internal/util"
 )
 
@@ -25,6 +30,7 @@ const (
 	Directory
 	File
 	Pattern
+	Content
 )
 
 var strictnessLevelNames = []string{
@@ -39,11 +45,84 @@ var ruleTypeNames = []string{
 	Directory:   "directory",
 	File:        "file",
 	Pattern:     "pattern",
+	Content:     "content",
+}
+
+// String returns the name used for the level in convention documents
+func (l StrictnessLevel) String() string {
+	if l < 0 || int(l) >= len(strictnessLevelNames) {
+		return fmt.Sprintf("StrictnessLevel(%d)", int(l))
+	}
+	return strictnessLevelNames[l]
+}
+
+// Enum returns the level names used in convention documents
+func (l StrictnessLevel) Enum() []string {
+	return slices.Clone(strictnessLevelNames)
+}
+
+// ParseStrictnessLevel converts a level name used in convention documents to a StrictnessLevel
+func ParseStrictnessLevel(name string) (StrictnessLevel, error) {
+	level := util.StringSearch(strictnessLevelNames, name)
+	if level == -1 {
+		return 0, fmt.Errorf("level %s is not valid: expected one of %s", name, strings.Join(strictnessLevelNames, ", "))
+	}
+	return StrictnessLevel(level), nil
+}
+
+// ParseRuleType converts a rule type name used in convention documents to a RuleType
+func ParseRuleType(name string) (RuleType, error) {
+	ruleType := util.StringSearch(ruleTypeNames, name)
+	if ruleType == -1 {
+		return Unspecified, fmt.Errorf("type %s is not valid: expected one of %s", name, strings.Join(Unspecified.Enum(), ", "))
+	}
+	return RuleType(ruleType), nil
+}
+
+// Enum returns the rule type names used in convention documents
+func (t RuleType) Enum() []string {
+	return slices.Clone(ruleTypeNames[Unspecified+1:])
+}
+
+// String returns the name used for the rule type in convention documents
+func (t RuleType) String() string {
+	if t < 0 || int(t) >= len(ruleTypeNames) {
+		return fmt.Sprintf("RuleType(%d)", int(t))
+	}
+	return ruleTypeNames[t]
 }
 
 type Convention struct {
-	Name  string `json:"name"`
-	Rules []Rule `json:"rules"`
+	Name string `json:"name"`
+	// Extends names the conventions whose rules are inherited, in order; later rules override earlier rules
+	// targeting the same item, and the convention's own rules override all inherited rules.
+	Extends []string `json:"extends,omitempty"`
+	// Remove lists the values of inherited rules which do not apply to this convention
+	Remove []string `json:"remove,omitempty"`
+	// Detect lists patterns, any of which marks a directory as a project this convention applies to
+	Detect []string `json:"detect,omitempty"`
+	Rules  []Rule   `json:"rules"`
+
+	// Source is the file a user-defined convention was loaded from, or empty for a built-in convention
+	Source string `json:"-"`
+}
+
+// BuiltIn reports whether the convention ships with ossify rather than being loaded from a file
+func (c *Convention) BuiltIn() bool {
+	return c.Source == ""
+}
+
+// Detected returns the Detect markers matched in dir; a convention without markers is never detected
+func (c *Convention) Detected(dir string) ([]string, error) {
+	var found []string
+	for _, marker := range c.Detect {
+		matches, err := pathmatch.Glob(dir, marker)
+		if err != nil {
+			return nil, fmt.Errorf("convention '%s' detect marker: %w", c.Name, err)
+		}
+		found = append(found, matches...)
+	}
+	return found, nil
 }
 
 // noinspection GoUnusedExportedFunction
@@ -51,10 +130,45 @@ func NewConvention(name string, rules []Rule) *Convention {
 	return &Convention{Name: name, Rules: rules}
 }
 
+// Rule is written to convention documents by MarshalJSON; its tags name the fields for the published schema
 type Rule struct {
-	Level StrictnessLevel
-	Type  RuleType
-	Value string
+	Level StrictnessLevel `json:"level" jsonschema:"required"`
+	Type  RuleType        `json:"type" jsonschema:"required"`
+	Value string          `json:"value" jsonschema:"required"`
+	// Contains is the literal text a Content rule looks for in the file named by Value.
+	Contains string `json:"contains,omitempty"`
+	// Matches is the regular expression a Content rule looks for in the file named by Value.
+	Matches string `json:"matches,omitempty"`
+	// Exclude lists patterns whose matches are ignored by a Pattern rule.
+	Exclude []string `json:"exclude,omitempty"`
+	// Each is a path, relative to every match of a Pattern rule, which must exist (or, for Prohibited rules,
+	// must not exist); e.g. a Value of "cmd/*/" with Each "main.go".
+	Each string `json:"each,omitempty"`
+	// Alternatives are paths, or patterns, accepted in place of Value by Directory, File and Pattern rules: the rule
+	// is satisfied when any of Value and Alternatives exists or, for Prohibited rules, when none of them exists;
+	// e.g. a Value of "README.md" with Alternatives "README.rst" and "README".
+	Alternatives []string `json:"alternatives,omitempty"`
+	// When is a precondition of the rule; while it does not hold, the rule is skipped rather than evaluated
+	When *Condition `json:"when,omitempty"`
+
+	// ID is an optional stable identifier of the rule, e.g. "license-present", used by reports to track it
+	ID string `json:"id,omitempty"`
+	// Description explains what the rule expects, in a sentence
+	Description string `json:"description,omitempty"`
+	// Rationale is a link explaining why the rule exists
+	Rationale string `json:"rationale,omitempty"`
+	// Remediation tells a developer how to fix a failure, e.g. "run `ossify license MIT > LICENSE`"
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// Condition is the precondition of a rule: the directory, file or pattern named by Value exists or, for Content
+// conditions, the file named by Value contains the text or regular expression; e.g. a Type of File and Value
+// "Dockerfile" for a rule requiring .dockerignore
+type Condition struct {
+	Type     RuleType `json:"type" jsonschema:"required"`
+	Value    string   `json:"value" jsonschema:"required"`
+	Contains string   `json:"contains,omitempty"`
+	Matches  string   `json:"matches,omitempty"`
 }
 
 // noinspection GoUnusedExportedFunction
@@ -63,72 +177,281 @@ func NewRule(level StrictnessLevel, ruleType RuleType, value string) *Rule {
 }
 
 func (r *Rule) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]interface{}{
+	m := map[string]interface{}{
 		"level": strictnessLevelNames[r.Level],
 		"type":  ruleTypeNames[r.Type],
 		"value": r.Value,
-	})
+	}
+	if r.Contains != "" {
+		m["contains"] = r.Contains
+	}
+	if r.Matches != "" {
+		m["matches"] = r.Matches
+	}
+	if len(r.Exclude) > 0 {
+		m["exclude"] = r.Exclude
+	}
+	if r.Each != "" {
+		m["each"] = r.Each
+	}
+	if len(r.Alternatives) > 0 {
+		m["alternatives"] = r.Alternatives
+	}
+	if r.When != nil {
+		m["when"] = r.When
+	}
+	for name, value := range map[string]string{
+		"id": r.ID, "description": r.Description, "rationale": r.Rationale, "remediation": r.Remediation,
+	} {
+		if value != "" {
+			m[name] = value
+		}
+	}
+	return json.Marshal(m)
 }
 
 func (r *Rule) UnmarshalJSON(data []byte) error {
 	other := &struct {
-		Level string `json:"level"`
-		Type  string `json:"type"`
-		Value string `json:"value"`
+		Level    string   `json:"level"`
+		Type     string   `json:"type"`
+		Value    string   `json:"value"`
+		Contains string   `json:"contains"`
+		Matches  string   `json:"matches"`
+		Exclude  []string `json:"exclude"`
+		Each     string   `json:"each"`
+
+		Alternatives []string   `json:"alternatives"`
+		When         *Condition `json:"when"`
+
+		ID          string `json:"id"`
+		Description string `json:"description"`
+		Rationale   string `json:"rationale"`
+		Remediation string `json:"remediation"`
 	}{}
 
 	if err := json.Unmarshal(data, &other); err != nil {
 		return err
 	}
 
-	ruleType := util.StringSearch(ruleTypeNames, other.Type)
-	if ruleType == -1 {
-		return fmt.Errorf("type %s is not valid", other.Type)
+	ruleType, err := ParseRuleType(other.Type)
+	if err != nil {
+		return err
 	}
 
-	level := util.StringSearch(strictnessLevelNames, other.Level)
-	if level == -1 {
-		return fmt.Errorf("level %s is not valid", other.Level)
+	level, err := ParseStrictnessLevel(other.Level)
+	if err != nil {
+		return err
+	}
+
+	if ruleType == Content {
+		if (other.Contains == "") == (other.Matches == "") {
+			return fmt.Errorf("content rule for %s must specify exactly one of contains or matches", other.Value)
+		}
+		if other.Matches != "" {
+			if _, err := regexp.Compile(other.Matches); err != nil {
+				return fmt.Errorf("content rule for %s has invalid matches expression: %w", other.Value, err)
+			}
+		}
+	}
+
+	if ruleType == Content && len(other.Alternatives) > 0 {
+		return fmt.Errorf("content rule for %s: alternatives are only valid for directory, file and pattern rules", other.Value)
+	}
+
+	if ruleType == Pattern {
+		for _, pattern := range slices.Concat([]string{other.Value}, other.Alternatives, other.Exclude) {
+			if err := pathmatch.Validate(pattern); err != nil {
+				return fmt.Errorf("pattern rule for %s: %w", other.Value, err)
+			}
+		}
+	} else if len(other.Exclude) > 0 || other.Each != "" {
+		return fmt.Errorf("%s rule for %s: exclude and each are only valid for pattern rules", other.Type, other.Value)
 	}
 
 	r.Value = other.Value
-	r.Type = RuleType(ruleType)
-	r.Level = StrictnessLevel(level)
+	r.Type = ruleType
+	r.Level = level
+	r.Contains = other.Contains
+	r.Matches = other.Matches
+	r.Exclude = other.Exclude
+	r.Each = other.Each
+	r.Alternatives = other.Alternatives
+	r.When = other.When
+	r.ID = other.ID
+	r.Description = other.Description
+	r.Rationale = other.Rationale
+	r.Remediation = other.Remediation
+
+	return nil
+}
+
+func (c *Condition) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{
+		"type":  ruleTypeNames[c.Type],
+		"value": c.Value,
+	}
+	if c.Contains != "" {
+		m["contains"] = c.Contains
+	}
+	if c.Matches != "" {
+		m["matches"] = c.Matches
+	}
+	return json.Marshal(m)
+}
+
+func (c *Condition) UnmarshalJSON(data []byte) error {
+	other := &struct {
+		Type     string `json:"type"`
+		Value    string `json:"value"`
+		Contains string `json:"contains"`
+		Matches  string `json:"matches"`
+	}{}
+
+	if err := json.Unmarshal(data, &other); err != nil {
+		return err
+	}
+
+	conditionType, err := ParseRuleType(other.Type)
+	if err != nil {
+		return fmt.Errorf("when: %w", err)
+	}
+
+	switch conditionType {
+	case Content:
+		if (other.Contains == "") == (other.Matches == "") {
+			return fmt.Errorf("when %s: content condition must specify exactly one of contains or matches", other.Value)
+		}
+		if other.Matches != "" {
+			if _, err := regexp.Compile(other.Matches); err != nil {
+				return fmt.Errorf("when %s: invalid matches expression: %w", other.Value, err)
+			}
+		}
+	case Pattern:
+		if err := pathmatch.Validate(other.Value); err != nil {
+			return fmt.Errorf("when %s: %w", other.Value, err)
+		}
+	}
+	if conditionType != Content && (other.Contains != "" || other.Matches != "") {
+		return fmt.Errorf("when %s: contains and matches are only valid for content conditions", other.Value)
+	}
+
+	c.Type = conditionType
+	c.Value = other.Value
+	c.Contains = other.Contains
+	c.Matches = other.Matches
 
 	return nil
 }
 
+// Holds reports whether the condition is met in dir
+func (c *Condition) Holds(dir string) bool {
+	return evaluateRule(Rule{Level: Required, Type: c.Type, Value: c.Value, Contains: c.Contains, Matches: c.Matches}, dir).Passed
+}
+
+// String describes the condition, e.g. "file Dockerfile exists" or `go.mod contains "toolchain"`
+func (c *Condition) String() string {
+	switch c.Type {
+	case Content:
+		if c.Matches != "" {
+			return fmt.Sprintf("%s matches %q", c.Value, c.Matches)
+		}
+		return fmt.Sprintf("%s contains %q", c.Value, c.Contains)
+	case Pattern:
+		return fmt.Sprintf("pattern %s matches", c.Value)
+	default:
+		return fmt.Sprintf("%s %s exists", c.Type, c.Value)
+	}
+}
+
 func (c *Convention) Print() error {
 	var str strings.Builder
 	str.WriteString(c.Name)
+	if len(c.Extends) > 0 {
+		str.WriteString(fmt.Sprintf(" (extends %s)", strings.Join(c.Extends, ", ")))
+	}
 	if len(c.Rules) == 0 {
 		str.WriteString(": No Rules Specified!\n")
 	} else {
 		str.WriteString("\n")
 		for _, r := range c.Rules {
-			str.WriteString(fmt.Sprintf("  - %-20s %-15s %-10s\n", r.Value, ruleTypeNames[r.Type], strictnessLevelNames[r.Level]))
+			str.WriteString(fmt.Sprintf("  - %-20s %-15s %-10s", r.Value, ruleTypeNames[r.Type], strictnessLevelNames[r.Level]))
+			if expectation := r.expectation(); expectation != "" {
+				str.WriteString(" " + expectation)
+			}
+			str.WriteString("\n")
+			for _, line := range r.Guidance() {
+				str.WriteString("      " + line + "\n")
+			}
 		}
 	}
 	_, err := fmt.Print(str.String())
 	return err
 }
 
+// Status is the outcome of a single rule evaluation, as counted in a CheckResult
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusWarn Status = "warn"
+	StatusSkip Status = "skip"
+	// StatusSuppressed is a failure waived by a Suppression
+	StatusSuppressed Status = "suppressed"
+)
+
 // RuleResult represents the result of evaluating a single rule
 type RuleResult struct {
 	Rule    Rule
 	Passed  bool
 	Message string
+	// Line is the 1-based line of a content match or violation, or 0 when not applicable
+	Line int
+	// Paths are the paths, relative to the checked directory, matched by a Pattern rule; for rules with Each,
+	// only the matches which violate the rule
+	Paths []string
+	// Suppression is the active suppression waiving a failed rule, if any
+	Suppression *Suppression
+	// Skipped is set when the rule was not evaluated because its When condition does not hold
+	Skipped bool
+}
+
+// Status classifies the result the same way Evaluate counts it: failed Preferred rules are warnings,
+// failed Optional rules and rules whose condition does not hold are skipped, suppressed failures are suppressed,
+// and any other failure is a failure.
+func (rr *RuleResult) Status() Status {
+	if rr.Skipped {
+		return StatusSkip
+	}
+	if rr.Passed {
+		return StatusPass
+	}
+	if rr.Suppression != nil {
+		return StatusSuppressed
+	}
+	switch rr.Rule.Level {
+	case Preferred:
+		return StatusWarn
+	case Optional:
+		return StatusSkip
+	default:
+		return StatusFail
+	}
 }
 
 // CheckResult represents the overall result of checking a convention against a directory
 type CheckResult struct {
 	Convention string
 	Directory  string
-	Results    []RuleResult
-	PassCount  int
-	FailCount  int
-	WarnCount  int
-	SkipCount  int
+	// Module is the path of Directory relative to the root of a recursive (monorepo) check, or empty
+	Module    string
+	Results   []RuleResult
+	PassCount int
+	FailCount int
+	WarnCount int
+	SkipCount int
+	// SuppressedCount is the number of failures waived by a Suppression
+	SuppressedCount int
 }
 
 // HasFailures returns true if any required rules failed or prohibited items exist
@@ -138,30 +461,56 @@ func (cr *CheckResult) HasFailures() bool {
 
 // Print outputs the check results to stdout
 func (cr *CheckResult) Print() {
-	fmt.Printf("Checking convention '%s' against directory: %s\n\n", cr.Convention, cr.Directory)
+	cr.Fprint(os.Stdout)
+}
+
+// Fprint writes the check results as a human-readable table to w
+func (cr *CheckResult) Fprint(w io.Writer) {
+	if cr.Module != "" {
+		_, _ = fmt.Fprintf(w, "Checking convention '%s' against module %s: %s\n\n", cr.Convention, cr.Module, cr.Directory)
+	} else {
+		_, _ = fmt.Fprintf(w, "Checking convention '%s' against directory: %s\n\n", cr.Convention, cr.Directory)
+	}
 
 	for _, r := range cr.Results {
 		status := "✓"
-		if !r.Passed {
-			switch r.Rule.Level {
-			case Required, Prohibited:
-				status = "✗"
-			case Preferred:
-				status = "⚠"
-			default:
-				status = "○"
-			}
+		switch r.Status() {
+		case StatusFail:
+			status = "✗"
+		case StatusWarn:
+			status = "⚠"
+		case StatusSkip:
+			status = "○"
+		case StatusSuppressed:
+			status = "⊘"
+		}
+		message := r.Message
+		if expectation := r.Rule.expectation(); expectation != "" {
+			message = fmt.Sprintf("%s (%s)", message, expectation)
 		}
-		fmt.Printf("  %s %-20s %-12s %-10s %s\n",
+		if r.Suppression != nil {
+			message = fmt.Sprintf("%s (suppressed: %s)", message, r.Suppression.Reason)
+		}
+		_, _ = fmt.Fprintf(w, "  %s %-20s %-12s %-10s %s\n",
 			status,
 			r.Rule.Value,
 			ruleTypeNames[r.Rule.Type],
 			strictnessLevelNames[r.Rule.Level],
-			r.Message)
+			message)
+		// failures explain themselves
+		if s := r.Status(); s == StatusFail || s == StatusWarn {
+			for _, line := range r.Rule.Guidance() {
+				_, _ = fmt.Fprintf(w, "      %s\n", line)
+			}
+		}
 	}
 
-	fmt.Printf("\nSummary: %d passed, %d failed, %d warnings, %d skipped\n",
+	_, _ = fmt.Fprintf(w, "\nSummary: %d passed, %d failed, %d warnings, %d skipped",
 		cr.PassCount, cr.FailCount, cr.WarnCount, cr.SkipCount)
+	if cr.SuppressedCount > 0 {
+		_, _ = fmt.Fprintf(w, ", %d suppressed", cr.SuppressedCount)
+	}
+	_, _ = fmt.Fprintln(w)
 }
 
 // Evaluate checks all rules in the convention against the specified directory
@@ -173,29 +522,47 @@ func (c *Convention) Evaluate(targetDir string) (*CheckResult, error) {
 	}
 
 	for _, rule := range c.Rules {
-		ruleResult := evaluateRule(rule, targetDir)
-		result.Results = append(result.Results, ruleResult)
-
-		if ruleResult.Passed {
-			result.PassCount++
-		} else {
-			switch rule.Level {
-			case Preferred:
-				result.WarnCount++
-			case Optional:
-				// technically unreachable for Optional
-				result.SkipCount++
-			default:
-				// Required, Prohibited, or unspecified
-				result.FailCount++
-			}
+		if rule.When != nil && !rule.When.Holds(targetDir) {
+			result.Results = append(result.Results, RuleResult{
+				Rule:    rule,
+				Skipped: true,
+				Message: "condition not met",
+			})
+			continue
 		}
+		result.Results = append(result.Results, evaluateRule(rule, targetDir))
 	}
+	result.Recount()
 
 	return result, nil
 }
 
+// Recount updates the counts from the status of each result
+func (cr *CheckResult) Recount() {
+	cr.PassCount, cr.FailCount, cr.WarnCount, cr.SkipCount, cr.SuppressedCount = 0, 0, 0, 0, 0
+	for i := range cr.Results {
+		switch cr.Results[i].Status() {
+		case StatusPass:
+			cr.PassCount++
+		case StatusWarn:
+			cr.WarnCount++
+		case StatusSkip:
+			// Optional failures, and rules whose condition does not hold
+			cr.SkipCount++
+		case StatusSuppressed:
+			cr.SuppressedCount++
+		default:
+			// Required, Prohibited, or unspecified
+			cr.FailCount++
+		}
+	}
+}
+
 func evaluateRule(rule Rule, targetDir string) RuleResult {
+	if len(rule.Alternatives) > 0 && rule.Type != Content {
+		return evaluateAlternatives(rule, targetDir)
+	}
+
 	result := RuleResult{Rule: rule}
 
 	targetPath := filepath.Join(targetDir, rule.Value)
@@ -262,16 +629,19 @@ func evaluateRule(rule Rule, targetDir string) RuleResult {
 		}
 
 	case Pattern:
-		// Pattern matching using glob
-		matches, globErr := filepath.Glob(filepath.Join(targetDir, rule.Value))
+		// Pattern matching using doublestar globs, respecting .gitignore
+		matches, globErr := pathmatch.Glob(targetDir, rule.Value, rule.Exclude...)
 		if globErr != nil {
 			result.Passed = false
 			result.Message = fmt.Sprintf("invalid pattern: %v", globErr)
+		} else if rule.Each != "" {
+			return evaluateEachRule(rule, targetDir, matches)
 		} else if len(matches) > 0 {
+			result.Paths = matches
 			switch rule.Level {
 			case Prohibited:
 				result.Passed = false
-				result.Message = fmt.Sprintf("prohibited pattern matched %d item(s)", len(matches))
+				result.Message = fmt.Sprintf("prohibited pattern matched %d item(s): %s", len(matches), summarizePaths(matches))
 			default:
 				result.Passed = true
 				result.Message = fmt.Sprintf("matched %d item(s)", len(matches))
@@ -293,6 +663,9 @@ func evaluateRule(rule Rule, targetDir string) RuleResult {
 			}
 		}
 
+	case Content:
+		return evaluateContentRule(rule, targetDir)
+
 	default:
 		result.Passed = false
 		result.Message = "unknown rule type"
@@ -300,3 +673,271 @@ func evaluateRule(rule Rule, targetDir string) RuleResult {
 
 	return result
 }
+
+// maxSummarizedPaths is the number of paths listed in a RuleResult message before the rest are counted
+const maxSummarizedPaths = 3
+
+// summarizePaths lists the first few paths, counting the remainder
+func summarizePaths(paths []string) string {
+	if len(paths) <= maxSummarizedPaths {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:maxSummarizedPaths], ", "), len(paths)-maxSummarizedPaths)
+}
+
+// evaluateAlternatives checks Value and each of the rule's Alternatives on its own. Prohibited rules fail with
+// every alternative which exists; other rules pass with the first alternative which exists.
+func evaluateAlternatives(rule Rule, targetDir string) RuleResult {
+	result := RuleResult{Rule: rule}
+
+	if rule.Level == Prohibited {
+		var violations []string
+		for _, value := range rule.Values() {
+			single := rule
+			single.Value, single.Alternatives = value, nil
+			r := evaluateRule(single, targetDir)
+			if r.Passed {
+				continue
+			}
+			violations = append(violations, fmt.Sprintf("%s: %s", value, r.Message))
+			if len(r.Paths) > 0 {
+				result.Paths = append(result.Paths, r.Paths...)
+			} else {
+				result.Paths = append(result.Paths, value)
+			}
+		}
+		if len(violations) > 0 {
+			result.Message = strings.Join(violations, "; ")
+		} else {
+			result.Passed = true
+			result.Message = "none present (good)"
+		}
+		return result
+	}
+
+	for _, value := range rule.Values() {
+		// evaluated as required, an alternative passes only when it exists
+		single := rule
+		single.Value, single.Alternatives, single.Level = value, nil, Required
+		if r := evaluateRule(single, targetDir); r.Passed {
+			result.Passed = true
+			result.Message = fmt.Sprintf("%s: %s", value, r.Message)
+			result.Paths = r.Paths
+			return result
+		}
+	}
+
+	switch rule.Level {
+	case Required:
+		result.Message = "none present"
+	case Preferred:
+		result.Message = "recommended but none present"
+	default:
+		result.Passed = true
+		result.Message = "none present (optional)"
+	}
+	return result
+}
+
+// evaluateEachRule checks that the rule's Each path exists relative to every match (or, for Prohibited rules,
+// relative to none of them). The matches violating the rule are recorded in Paths.
+func evaluateEachRule(rule Rule, targetDir string, matches []string) RuleResult {
+	result := RuleResult{Rule: rule}
+
+	var present, missing []string
+	for _, match := range matches {
+		if _, err := os.Stat(filepath.Join(targetDir, filepath.FromSlash(match), rule.Each)); err == nil {
+			present = append(present, match)
+		} else {
+			missing = append(missing, match)
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		result.Passed = rule.Level != Required && rule.Level != Preferred
+		result.Message = "no matches"
+		if rule.Level == Preferred {
+			result.Message = "recommended but no matches"
+		}
+	case rule.Level == Prohibited:
+		result.Paths = present
+		result.Passed = len(present) == 0
+		if result.Passed {
+			result.Message = fmt.Sprintf("none of %d item(s) contain %s (good)", len(matches), rule.Each)
+		} else {
+			result.Message = fmt.Sprintf("prohibited %s found in %d of %d item(s): %s", rule.Each, len(present), len(matches), summarizePaths(present))
+		}
+	default:
+		result.Paths = missing
+		switch {
+		case len(missing) == 0:
+			result.Passed = true
+			result.Message = fmt.Sprintf("all %d item(s) contain %s", len(matches), rule.Each)
+		case rule.Level == Required || rule.Level == Preferred:
+			result.Passed = false
+			result.Message = fmt.Sprintf("%s missing from %d of %d item(s): %s", rule.Each, len(missing), len(matches), summarizePaths(missing))
+		default:
+			result.Passed = true
+			result.Message = fmt.Sprintf("%s not present in %d of %d item(s) (optional)", rule.Each, len(missing), len(matches))
+		}
+	}
+
+	return result
+}
+
+// evaluateContentRule checks whether the file named by the rule's Value contains the rule's
+// literal text or regular expression, recording the line of the first match.
+func evaluateContentRule(rule Rule, targetDir string) RuleResult {
+	result := RuleResult{Rule: rule}
+
+	targetPath := filepath.Join(targetDir, rule.Value)
+	info, err := os.Stat(targetPath)
+	if err != nil || info.IsDir() {
+		// File does not exist, so its content can neither match nor violate
+		switch rule.Level {
+		case Prohibited:
+			result.Passed = true
+			result.Message = "file not present (good)"
+		case Required:
+			result.Passed = false
+			result.Message = "missing"
+		case Preferred:
+			result.Passed = false
+			result.Message = "recommended but missing"
+		default:
+			result.Passed = true
+			result.Message = "not present (optional)"
+		}
+		return result
+	}
+
+	line, err := findContent(targetPath, rule)
+	if err != nil {
+		result.Passed = false
+		result.Message = err.Error()
+		return result
+	}
+
+	if line > 0 {
+		result.Line = line
+		switch rule.Level {
+		case Prohibited:
+			result.Passed = false
+			result.Message = fmt.Sprintf("prohibited content found at line %d", line)
+		default:
+			result.Passed = true
+			result.Message = fmt.Sprintf("found at line %d", line)
+		}
+	} else {
+		switch rule.Level {
+		case Prohibited:
+			result.Passed = true
+			result.Message = "content not present (good)"
+		case Required:
+			result.Passed = false
+			result.Message = "content not found"
+		case Preferred:
+			result.Passed = false
+			result.Message = "recommended but content not found"
+		default:
+			result.Passed = true
+			result.Message = "content not present (optional)"
+		}
+	}
+
+	return result
+}
+
+// findContent returns the 1-based line of the first match of the rule's Contains or Matches
+// expectation in the file at path, or 0 if there is no match. Both are matched line by line.
+func findContent(path string, rule Rule) (int, error) {
+	var matcher func(line string) bool
+	switch {
+	case rule.Matches != "":
+		re, err := regexp.Compile(rule.Matches)
+		if err != nil {
+			return 0, fmt.Errorf("invalid matches expression: %v", err)
+		}
+		matcher = re.MatchString
+	case rule.Contains != "":
+		matcher = func(line string) bool { return strings.Contains(line, rule.Contains) }
+	default:
+		return 0, fmt.Errorf("content rule requires contains or matches")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if matcher(scanner.Text()) {
+			return lineNumber, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("reading file: %v", err)
+	}
+
+	return 0, nil
+}
+
+// Guidance describes the rule to a developer: its ID and description, how to fix a failure and where to read
+// why it exists. It is empty for a rule without them.
+func (r *Rule) Guidance() []string {
+	var lines []string
+	switch {
+	case r.ID != "" && r.Description != "":
+		lines = append(lines, fmt.Sprintf("[%s] %s", r.ID, r.Description))
+	case r.ID != "":
+		lines = append(lines, fmt.Sprintf("[%s]", r.ID))
+	case r.Description != "":
+		lines = append(lines, r.Description)
+	}
+	if r.Remediation != "" {
+		lines = append(lines, "fix: "+r.Remediation)
+	}
+	if r.Rationale != "" {
+		lines = append(lines, "see: "+r.Rationale)
+	}
+	return lines
+}
+
+// Values returns Value followed by the rule's Alternatives
+func (r *Rule) Values() []string {
+	return append([]string{r.Value}, r.Alternatives...)
+}
+
+// expectation describes what a Content rule looks for, or the qualifiers of a Pattern rule, along with any
+// alternatives and condition, or returns an empty string when a rule has nothing beyond its value.
+func (r *Rule) expectation() string {
+	var parts []string
+	if len(r.Alternatives) > 0 {
+		parts = append(parts, "or "+strings.Join(r.Alternatives, ", "))
+	}
+	switch r.Type {
+	case Content:
+		if r.Matches != "" {
+			parts = append(parts, fmt.Sprintf("matches %q", r.Matches))
+		} else {
+			parts = append(parts, fmt.Sprintf("contains %q", r.Contains))
+		}
+	case Pattern:
+		if r.Each != "" {
+			parts = append(parts, "each "+r.Each)
+		}
+		if len(r.Exclude) > 0 {
+			parts = append(parts, "excluding "+strings.Join(r.Exclude, ", "))
+		}
+	}
+	if r.When != nil {
+		parts = append(parts, "when "+r.When.String())
+	}
+	return strings.Join(parts, "; ")
+}
@@ -1,8 +1,11 @@
 package model
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -307,3 +310,289 @@ func TestCheckResult_HasFailures(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateRule_Content(t *testing.T) {
+	readme := "# ossify\n\nSome description.\n\n## Installation\n\ngo install\n"
+	license := "Copyright <YEAR> <COPYRIGHT HOLDER>\n\nPermission is hereby granted\n"
+
+	tests := []struct {
+		name        string
+		rule        Rule
+		wantPassed  bool
+		wantLine    int
+		wantMessage string
+	}{
+		{"required regex found", Rule{Level: Required, Type: Content, Value: "README.md", Matches: `^#+\s*Installation`}, true, 5, "found at line 5"},
+		{"required regex missing", Rule{Level: Required, Type: Content, Value: "README.md", Matches: `^#+\s*Usage`}, false, 0, "content not found"},
+		{"required literal found", Rule{Level: Required, Type: Content, Value: "README.md", Contains: "go install"}, true, 7, "found at line 7"},
+		{"required file missing", Rule{Level: Required, Type: Content, Value: "CONTRIBUTING.md", Contains: "pull request"}, false, 0, "missing"},
+		{"prohibited literal found", Rule{Level: Prohibited, Type: Content, Value: "LICENSE", Contains: "<COPYRIGHT HOLDER>"}, false, 1, "prohibited content found at line 1"},
+		{"prohibited literal absent", Rule{Level: Prohibited, Type: Content, Value: "LICENSE", Contains: "<OWNER>"}, true, 0, "content not present (good)"},
+		{"prohibited file missing", Rule{Level: Prohibited, Type: Content, Value: "NOTICE", Contains: "<OWNER>"}, true, 0, "file not present (good)"},
+		{"preferred content missing", Rule{Level: Preferred, Type: Content, Value: "README.md", Contains: "## License"}, false, 0, "recommended but content not found"},
+		{"optional content missing", Rule{Level: Optional, Type: Content, Value: "README.md", Contains: "## FAQ"}, true, 0, "content not present (optional)"},
+		{"invalid regex", Rule{Level: Required, Type: Content, Value: "README.md", Matches: `(`}, false, 0, "invalid matches expression: error parsing regexp: missing closing ): `(`"},
+	}
+
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "README.md"), []byte(readme), 0644); err != nil {
+		t.Fatalf("failed to write README.md: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "LICENSE"), []byte(license), 0644); err != nil {
+		t.Fatalf("failed to write LICENSE: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateRule(tt.rule, tempDir)
+			if got.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v (message: %s)", got.Passed, tt.wantPassed, got.Message)
+			}
+			if got.Line != tt.wantLine {
+				t.Errorf("Line = %d, want %d", got.Line, tt.wantLine)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestEvaluateRule_Pattern(t *testing.T) {
+	tempDir := setupTestDir(t, map[string]bool{
+		"a.orig":           false,
+		"b/c.orig":         false,
+		"b/d/e.orig":       false,
+		"b/d/f/g.orig":     false,
+		"cmd/app/main.go":  false,
+		"cmd/tool/tool.go": false,
+		"cmd/empty":        true,
+	})
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	tests := []struct {
+		name        string
+		rule        Rule
+		wantPassed  bool
+		wantPaths   []string
+		wantMessage string
+	}{
+		{
+			name:        "prohibited recursive matches are listed and truncated",
+			rule:        Rule{Level: Prohibited, Type: Pattern, Value: "**/*.orig"},
+			wantPaths:   []string{"a.orig", "b/c.orig", "b/d/e.orig", "b/d/f/g.orig"},
+			wantMessage: "prohibited pattern matched 4 item(s): a.orig, b/c.orig, b/d/e.orig and 1 more",
+		},
+		{
+			name:        "excluded matches are ignored",
+			rule:        Rule{Level: Prohibited, Type: Pattern, Value: "**/*.orig", Exclude: []string{"b/d/"}},
+			wantPaths:   []string{"a.orig", "b/c.orig"},
+			wantMessage: "prohibited pattern matched 2 item(s): a.orig, b/c.orig",
+		},
+		{
+			name:        "required each reports matches missing the item",
+			rule:        Rule{Level: Required, Type: Pattern, Value: "cmd/*/", Each: "main.go"},
+			wantPaths:   []string{"cmd/empty", "cmd/tool"},
+			wantMessage: "main.go missing from 2 of 3 item(s): cmd/empty, cmd/tool",
+		},
+		{
+			name:        "prohibited each reports matches containing the item",
+			rule:        Rule{Level: Prohibited, Type: Pattern, Value: "cmd/*/", Each: "main.go"},
+			wantPaths:   []string{"cmd/app"},
+			wantMessage: "prohibited main.go found in 1 of 3 item(s): cmd/app",
+		},
+		{
+			name:        "each passes when every match has the item",
+			rule:        Rule{Level: Required, Type: Pattern, Value: "cmd/a*/", Each: "main.go"},
+			wantPassed:  true,
+			wantMessage: "all 1 item(s) contain main.go",
+		},
+		{
+			name:        "required each without matches fails",
+			rule:        Rule{Level: Required, Type: Pattern, Value: "services/*/", Each: "main.go"},
+			wantMessage: "no matches",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateRule(tt.rule, tempDir)
+			if got.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v (message: %s)", got.Passed, tt.wantPassed, got.Message)
+			}
+			if !reflect.DeepEqual(got.Paths, tt.wantPaths) {
+				t.Errorf("Paths = %v, want %v", got.Paths, tt.wantPaths)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestCheckResult_Fprint_Guidance(t *testing.T) {
+	rule := Rule{
+		Level: Required, Type: File, Value: "LICENSE",
+		ID: "license-present", Description: "Every repository has a license",
+		Rationale: "https://opensource.guide/legal/", Remediation: "run `ossify license MIT > LICENSE`",
+	}
+	result := &CheckResult{Convention: "Ours", Directory: "/tmp/project", Results: []RuleResult{
+		{Rule: rule, Message: "missing"},
+		{Rule: Rule{Level: Required, Type: File, Value: "README.md", ID: "readme-present"}, Passed: true, Message: "found"},
+	}}
+	result.Recount()
+
+	var b strings.Builder
+	result.Fprint(&b)
+	for _, want := range []string{
+		"      [license-present] Every repository has a license\n",
+		"      fix: run `ossify license MIT > LICENSE`\n",
+		"      see: https://opensource.guide/legal/\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Fprint() missing %q:\n%s", want, b.String())
+		}
+	}
+	// passing rules need no explanation
+	if strings.Contains(b.String(), "readme-present") {
+		t.Errorf("Fprint() explains a passing rule:\n%s", b.String())
+	}
+
+	data, err := json.Marshal(&rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Rule
+	if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, rule) {
+		t.Errorf("round-trip = %+v, %v; want %+v", decoded, err, rule)
+	}
+}
+
+func TestEvaluateRule_Alternatives(t *testing.T) {
+	tempDir := setupTestDir(t, map[string]bool{
+		"README.rst":    false,
+		"npm-debug.log": false,
+		"docs":          true,
+	})
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	tests := []struct {
+		name        string
+		rule        Rule
+		wantPassed  bool
+		wantPaths   []string
+		wantMessage string
+	}{
+		{
+			name:        "required passes with the first alternative present",
+			rule:        Rule{Level: Required, Type: File, Value: "README.md", Alternatives: []string{"README", "README.rst"}},
+			wantPassed:  true,
+			wantMessage: "README.rst: found",
+		},
+		{
+			name:        "required fails with none present",
+			rule:        Rule{Level: Required, Type: Directory, Value: "doc", Alternatives: []string{"documentation"}},
+			wantMessage: "none present",
+		},
+		{
+			name:        "preferred warns with none present",
+			rule:        Rule{Level: Preferred, Type: File, Value: "CHANGELOG.md", Alternatives: []string{"CHANGES.md"}},
+			wantMessage: "recommended but none present",
+		},
+		{
+			name:        "optional passes with none present",
+			rule:        Rule{Level: Optional, Type: File, Value: "NOTICE", Alternatives: []string{"NOTICE.md"}},
+			wantPassed:  true,
+			wantMessage: "none present (optional)",
+		},
+		{
+			name:        "prohibited fails with every alternative present",
+			rule:        Rule{Level: Prohibited, Type: File, Value: "yarn-error.log", Alternatives: []string{"npm-debug.log", "README.rst"}},
+			wantPaths:   []string{"npm-debug.log", "README.rst"},
+			wantMessage: "npm-debug.log: prohibited file exists; README.rst: prohibited file exists",
+		},
+		{
+			name:        "prohibited passes with none present",
+			rule:        Rule{Level: Prohibited, Type: Directory, Value: "vendor", Alternatives: []string{"third_party"}},
+			wantPassed:  true,
+			wantMessage: "none present (good)",
+		},
+		{
+			name:        "pattern alternatives",
+			rule:        Rule{Level: Required, Type: Pattern, Value: "*.md", Alternatives: []string{"*.rst"}},
+			wantPassed:  true,
+			wantPaths:   []string{"README.rst"},
+			wantMessage: "*.rst: matched 1 item(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateRule(tt.rule, tempDir)
+			if got.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v (message: %s)", got.Passed, tt.wantPassed, got.Message)
+			}
+			if !reflect.DeepEqual(got.Paths, tt.wantPaths) {
+				t.Errorf("Paths = %v, want %v", got.Paths, tt.wantPaths)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+
+	// alternatives are one rule, printed on one line
+	result := &CheckResult{Convention: "Docs", Directory: tempDir, Results: []RuleResult{evaluateRule(tests[0].rule, tempDir)}}
+	result.Recount()
+	var b strings.Builder
+	result.Fprint(&b)
+	if want := "README.rst: found (or README, README.rst)\n"; !strings.Contains(b.String(), want) {
+		t.Errorf("Fprint() missing %q:\n%s", want, b.String())
+	}
+
+	var rule Rule
+	if err := json.Unmarshal([]byte(`{"level":"required","type":"content","value":"README.md","contains":"x","alternatives":["README"]}`), &rule); err == nil {
+		t.Error("Unmarshal() accepted alternatives on a content rule")
+	}
+}
+
+func TestConvention_Evaluate_When(t *testing.T) {
+	tempDir := setupTestDir(t, map[string]bool{
+		"Dockerfile": false,
+		"go.mod":     false,
+		"vendor":     true,
+	})
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	convention := Convention{
+		Name: "Conditional",
+		Rules: []Rule{
+			{Level: Required, Type: File, Value: ".dockerignore", When: &Condition{Type: File, Value: "Dockerfile"}},
+			{Level: Prohibited, Type: Directory, Value: "vendor", When: &Condition{Type: Content, Value: "go.mod", Contains: "test"}},
+			{Level: Required, Type: File, Value: "package-lock.json", When: &Condition{Type: File, Value: "package.json"}},
+			{Level: Required, Type: Directory, Value: "proto", When: &Condition{Type: Pattern, Value: "**/*.proto"}},
+		},
+	}
+
+	result, err := convention.Evaluate(tempDir)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.PassCount != 0 || result.FailCount != 2 || result.SkipCount != 2 {
+		t.Errorf("Evaluate() pass=%d fail=%d skip=%d, want pass=0 fail=2 skip=2",
+			result.PassCount, result.FailCount, result.SkipCount)
+	}
+
+	wantStatus := []Status{StatusFail, StatusFail, StatusSkip, StatusSkip}
+	for i, r := range result.Results {
+		if got := r.Status(); got != wantStatus[i] {
+			t.Errorf("rule %s status = %s, want %s (message: %s)", r.Rule.Value, got, wantStatus[i], r.Message)
+		}
+	}
+
+	var b strings.Builder
+	result.Fprint(&b)
+	if want := "condition not met (when file package.json exists)"; !strings.Contains(b.String(), want) {
+		t.Errorf("Fprint() missing %q:\n%s", want, b.String())
+	}
+}
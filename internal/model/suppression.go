@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Suppression waives a rule of a convention for one project, with the reason it does not apply
+type Suppression struct {
+	// Convention is the name of the convention (case-insensitive), or "*" for any convention
+	Convention string
+	// Value is the value of the waived rule
+	Value  string
+	Reason string
+	// Expires is the day after which the suppression no longer applies, or the zero time when it does not expire
+	Expires time.Time
+	// Source is where the suppression was declared, e.g. ".ossifyignore:3"
+	Source string
+}
+
+// Matches reports whether the suppression applies to rule within the named convention
+func (s *Suppression) Matches(convention string, rule Rule) bool {
+	return (s.Convention == "*" || strings.EqualFold(s.Convention, convention)) && s.Value == rule.Value
+}
+
+// Expired reports whether the suppression has expired as of now
+func (s *Suppression) Expired(now time.Time) bool {
+	if s.Expires.IsZero() {
+		return false
+	}
+	// the suppression applies through the whole of its expiry day
+	return !now.Before(s.Expires.AddDate(0, 0, 1))
+}
+
+// Suppress marks the failing results waived by an active suppression as suppressed, and notes expired
+// suppressions in the message of results which still fail. The counts are updated to match.
+func (cr *CheckResult) Suppress(suppressions []Suppression, now time.Time) {
+	for i := range cr.Results {
+		r := &cr.Results[i]
+		if r.Passed || r.Skipped {
+			continue
+		}
+		for j := range suppressions {
+			s := suppressions[j]
+			if !s.Matches(cr.Convention, r.Rule) {
+				continue
+			}
+			if s.Expired(now) {
+				r.Message = fmt.Sprintf("%s (suppression expired %s: %s)", r.Message, s.Expires.Format(time.DateOnly), s.Reason)
+				continue
+			}
+			r.Suppression = &s
+			break
+		}
+	}
+	cr.Recount()
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckResult_Suppress(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.Local)
+	result := &CheckResult{
+		Convention: "Go",
+		Results: []RuleResult{
+			{Rule: Rule{Level: Prohibited, Type: Directory, Value: "src"}, Message: "prohibited directory exists"},
+			{Rule: Rule{Level: Required, Type: Directory, Value: "docs"}, Message: "missing"},
+			{Rule: Rule{Level: Required, Type: File, Value: "LICENSE"}, Message: "missing"},
+			{Rule: Rule{Level: Required, Type: File, Value: "README.md"}, Passed: true, Message: "found"},
+		},
+	}
+	result.Recount()
+
+	result.Suppress([]Suppression{
+		{Convention: "go", Value: "src", Reason: "generated SDK", Expires: time.Date(2025, 6, 15, 0, 0, 0, 0, time.Local)},
+		{Convention: "*", Value: "docs", Reason: "docs live in the wiki", Expires: time.Date(2025, 6, 14, 0, 0, 0, 0, time.Local)},
+		{Convention: "Node.js", Value: "LICENSE", Reason: "other convention"},
+		{Convention: "*", Value: "README.md", Reason: "passing rules are not suppressed"},
+	}, now)
+
+	want := []Status{StatusSuppressed, StatusFail, StatusFail, StatusPass}
+	for i, r := range result.Results {
+		if got := r.Status(); got != want[i] {
+			t.Errorf("result %s status = %s, want %s", r.Rule.Value, got, want[i])
+		}
+	}
+
+	if result.SuppressedCount != 1 || result.FailCount != 2 || result.PassCount != 1 {
+		t.Errorf("counts = %d suppressed, %d failed, %d passed", result.SuppressedCount, result.FailCount, result.PassCount)
+	}
+	if !strings.Contains(result.Results[1].Message, "suppression expired 2025-06-14: docs live in the wiki") {
+		t.Errorf("expired suppression not noted: %q", result.Results[1].Message)
+	}
+	if !result.HasFailures() {
+		t.Errorf("expected failures to remain")
+	}
+}
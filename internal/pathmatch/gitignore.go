@@ -0,0 +1,99 @@
+package pathmatch
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// ignoreRule is a single pattern from a .gitignore file
+type ignoreRule struct {
+	// base is the slash-separated directory containing the .gitignore, relative to the walked root
+	base    string
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// ignorer accumulates the rules of the .gitignore files encountered during a walk
+type ignorer struct {
+	rules []ignoreRule
+}
+
+// load reads the .gitignore in dir (relative to root), if any
+func (ig *ignorer) load(root, dir string) {
+	f, err := os.Open(filepath.Join(root, filepath.FromSlash(dir), ".gitignore"))
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(dir, scanner.Text()); ok {
+			ig.rules = append(ig.rules, rule)
+		}
+	}
+}
+
+// parseIgnoreLine parses one line of a .gitignore found in base, following the gitignore rules for comments,
+// negation, directory-only patterns and anchoring.
+func parseIgnoreLine(base, line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		// escaped leading "#" or "!"
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// a pattern with a slash anywhere but the end is relative to the .gitignore; otherwise it matches at any depth
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		line = "**/" + line
+	}
+
+	if line == "" || !doublestar.ValidatePattern(line) {
+		return ignoreRule{}, false
+	}
+	rule.pattern = line
+	return rule, true
+}
+
+// ignored reports whether the slash-separated path rel is ignored. As in git, the last matching rule wins.
+func (ig *ignorer) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range ig.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		target := rel
+		if rule.base != "." {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			target = strings.TrimPrefix(rel, rule.base+"/")
+		}
+
+		if doublestar.MatchUnvalidated(rule.pattern, target) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,139 @@
+// Package pathmatch finds paths within a directory tree using doublestar glob patterns, skipping .git and
+// anything excluded by .gitignore files.
+package pathmatch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// WalkFunc is called for each path visited by Walk with the path relative to the walked root, using forward
+// slashes. Returning fs.SkipDir from a directory skips its contents.
+type WalkFunc func(rel string, d fs.DirEntry) error
+
+// Walk visits every path under root except .git and paths ignored by the .gitignore files of root and its
+// subdirectories. Ignored directories are not descended into.
+func Walk(root string, fn WalkFunc) error {
+	return walk(root, ".", fn)
+}
+
+// walk visits the paths under the slash-separated start directory, relative to root. The .gitignore files of
+// root and every directory between root and start are honored.
+func walk(root, start string, fn WalkFunc) error {
+	ignores := &ignorer{}
+	if start != "." {
+		dir := "."
+		for _, segment := range strings.Split(start, "/") {
+			ignores.load(root, dir)
+			dir = path.Join(dir, segment)
+			if ignores.ignored(dir, true) {
+				return nil
+			}
+		}
+	}
+
+	return filepath.WalkDir(filepath.Join(root, filepath.FromSlash(start)), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if rel != "." {
+			if d.Name() == ".git" || ignores.ignored(rel, d.IsDir()) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
+
+		if d.IsDir() {
+			ignores.load(root, rel)
+			if rel == "." {
+				return nil
+			}
+		}
+
+		return fn(rel, d)
+	})
+}
+
+// Validate reports whether pattern is a valid doublestar pattern
+func Validate(pattern string) error {
+	if !doublestar.ValidatePattern(strings.TrimSuffix(filepath.ToSlash(pattern), "/")) {
+		return fmt.Errorf("invalid pattern %q", pattern)
+	}
+	return nil
+}
+
+// Glob returns the slash-separated paths under root, relative to root, which match pattern and none of excludes.
+// Patterns use forward slashes and support "**" to match any number of directories; a pattern ending in "/"
+// matches only directories. Results are in lexical order.
+func Glob(root, pattern string, excludes ...string) ([]string, error) {
+	if err := Validate(pattern); err != nil {
+		return nil, err
+	}
+	for _, exclude := range excludes {
+		if err := Validate(exclude); err != nil {
+			return nil, err
+		}
+	}
+
+	pattern = filepath.ToSlash(pattern)
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = path.Clean(strings.TrimSuffix(pattern, "/"))
+	if pattern == "." || strings.HasPrefix(pattern, "../") || pattern == ".." {
+		return nil, fmt.Errorf("pattern %q must be within the checked directory", pattern)
+	}
+
+	base, _ := doublestar.SplitPattern(pattern)
+	// without "**", nothing deeper than the pattern itself can match
+	maxDepth := -1
+	if !strings.Contains(pattern, "**") {
+		maxDepth = strings.Count(pattern, "/") + 1
+	}
+
+	var matches []string
+	err := walk(root, base, func(rel string, d fs.DirEntry) error {
+		if (!dirOnly || d.IsDir()) && doublestar.MatchUnvalidated(pattern, rel) && !excluded(rel, excludes) {
+			matches = append(matches, rel)
+		}
+		if d.IsDir() && maxDepth > 0 && strings.Count(rel, "/")+1 >= maxDepth {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// excluded reports whether rel matches any of excludes. An exclude pattern ending in "/" excludes a directory
+// and everything below it.
+func excluded(rel string, excludes []string) bool {
+	for _, exclude := range excludes {
+		exclude = filepath.ToSlash(exclude)
+		if strings.HasSuffix(exclude, "/") {
+			exclude += "**"
+		}
+		if doublestar.MatchUnvalidated(exclude, rel) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package pathmatch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func setupTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		fullPath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create parent dir for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestGlob(t *testing.T) {
+	dir := setupTree(t, map[string]string{
+		".gitignore":               "/build/\n*.log\n!keep.log\n",
+		"a.orig":                   "",
+		"main.go":                  "",
+		"keep.log":                 "",
+		"debug.log":                "",
+		"build/out.orig":           "",
+		"cmd/app/main.go":          "",
+		"cmd/app/app.orig":         "",
+		"cmd/tool/tool.go":         "",
+		"pkg/.gitignore":           "generated/\n",
+		"pkg/generated/x.orig":     "",
+		"pkg/util/deep/y.orig":     "",
+		"vendor/lib/z.orig":        "",
+		".git/objects/aa/bb.orig":  "",
+		"docs/build/page.orig":     "",
+		"cmd/internal/gen/main.go": "",
+	})
+
+	tests := []struct {
+		name     string
+		pattern  string
+		excludes []string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "top level only", pattern: "*.orig", want: []string{"a.orig"}},
+		{name: "recursive", pattern: "**/*.orig", want: []string{"a.orig", "cmd/app/app.orig", "docs/build/page.orig", "pkg/util/deep/y.orig", "vendor/lib/z.orig"}},
+		{name: "exclude directory", pattern: "**/*.orig", excludes: []string{"vendor/", "docs/**"}, want: []string{"a.orig", "cmd/app/app.orig", "pkg/util/deep/y.orig"}},
+		{name: "directories only", pattern: "cmd/*/", want: []string{"cmd/app", "cmd/internal", "cmd/tool"}},
+		{name: "nested files", pattern: "cmd/**/main.go", want: []string{"cmd/app/main.go", "cmd/internal/gen/main.go"}},
+		{name: "gitignore negation", pattern: "*.log", want: []string{"keep.log"}},
+		{name: "literal path", pattern: "cmd/tool/tool.go", want: []string{"cmd/tool/tool.go"}},
+		{name: "no matches", pattern: "**/*.rej", want: nil},
+		{name: "invalid", pattern: "[", wantErr: true},
+		{name: "outside", pattern: "../*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Glob(dir, tt.pattern, tt.excludes...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Glob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Glob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIgnoreLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   ignoreRule
+		wantOk bool
+	}{
+		{"", ignoreRule{}, false},
+		{"# comment", ignoreRule{}, false},
+		{"*.log", ignoreRule{base: ".", pattern: "**/*.log"}, true},
+		{"!keep.log", ignoreRule{base: ".", pattern: "**/keep.log", negate: true}, true},
+		{"/build/", ignoreRule{base: ".", pattern: "build", dirOnly: true}, true},
+		{"docs/*.html", ignoreRule{base: ".", pattern: "docs/*.html"}, true},
+		{`\#file`, ignoreRule{base: ".", pattern: "**/#file"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := parseIgnoreLine(".", tt.line)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("parseIgnoreLine(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
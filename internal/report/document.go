@@ -0,0 +1,184 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/jimschubert/ossify/internal/config"
+	"github.com/jimschubert/ossify/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Document is the versioned schema written by the json and yaml formats
+type Document struct {
+	Version int                `json:"version" yaml:"version"`
+	Tool    Tool               `json:"tool" yaml:"tool"`
+	Passed  bool               `json:"passed" yaml:"passed"`
+	Summary Summary            `json:"summary" yaml:"summary"`
+	Results []ConventionResult `json:"results" yaml:"results"`
+}
+
+// Tool identifies the program which produced a Document
+type Tool struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// Summary counts rule outcomes
+type Summary struct {
+	Passed     int `json:"passed" yaml:"passed"`
+	Failed     int `json:"failed" yaml:"failed"`
+	Warnings   int `json:"warnings" yaml:"warnings"`
+	Skipped    int `json:"skipped" yaml:"skipped"`
+	Suppressed int `json:"suppressed" yaml:"suppressed"`
+}
+
+// ConventionResult is the outcome of checking one convention against one directory
+type ConventionResult struct {
+	Convention string       `json:"convention" yaml:"convention"`
+	Directory  string       `json:"directory" yaml:"directory"`
+	Module     string       `json:"module,omitempty" yaml:"module,omitempty"`
+	Passed     bool         `json:"passed" yaml:"passed"`
+	Summary    Summary      `json:"summary" yaml:"summary"`
+	Rules      []RuleResult `json:"rules" yaml:"rules"`
+}
+
+// RuleResult is the outcome of a single rule
+type RuleResult struct {
+	ID       string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Level    string   `json:"level" yaml:"level"`
+	Type     string   `json:"type" yaml:"type"`
+	Value    string   `json:"value" yaml:"value"`
+	Contains string   `json:"contains,omitempty" yaml:"contains,omitempty"`
+	Matches  string   `json:"matches,omitempty" yaml:"matches,omitempty"`
+	Exclude  []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	Each     string   `json:"each,omitempty" yaml:"each,omitempty"`
+	Status   string   `json:"status" yaml:"status"`
+	Passed   bool     `json:"passed" yaml:"passed"`
+	Message  string   `json:"message" yaml:"message"`
+	Line     int      `json:"line,omitempty" yaml:"line,omitempty"`
+	Paths    []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	// Alternatives are accepted in place of Value
+	Alternatives []string `json:"alternatives,omitempty" yaml:"alternatives,omitempty"`
+	// When is the condition under which the rule applies; while it does not hold, the rule is skipped
+	When *Condition `json:"when,omitempty" yaml:"when,omitempty"`
+	// Description, Rationale and Remediation explain the rule and how to fix a failure, when the convention does
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Rationale   string `json:"rationale,omitempty" yaml:"rationale,omitempty"`
+	Remediation string `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+	// Suppression is set when the rule failed but was waived
+	Suppression *Suppression `json:"suppression,omitempty" yaml:"suppression,omitempty"`
+}
+
+// Condition is the precondition of a conditional rule
+type Condition struct {
+	Type     string `json:"type" yaml:"type"`
+	Value    string `json:"value" yaml:"value"`
+	Contains string `json:"contains,omitempty" yaml:"contains,omitempty"`
+	Matches  string `json:"matches,omitempty" yaml:"matches,omitempty"`
+}
+
+// Suppression describes why a failed rule was waived
+type Suppression struct {
+	Reason  string `json:"reason" yaml:"reason"`
+	Expires string `json:"expires,omitempty" yaml:"expires,omitempty"`
+	Source  string `json:"source" yaml:"source"`
+}
+
+// NewDocument converts check results into the versioned report schema
+func NewDocument(results []*model.CheckResult) *Document {
+	doc := &Document{
+		Version: SchemaVersion,
+		Tool:    Tool{Name: "ossify", Version: config.Version},
+		Passed:  true,
+		Results: make([]ConventionResult, 0, len(results)),
+	}
+
+	for _, result := range results {
+		converted := newConventionResult(result)
+		doc.Results = append(doc.Results, converted)
+		doc.Summary.add(converted.Summary)
+		if !converted.Passed {
+			doc.Passed = false
+		}
+	}
+
+	return doc
+}
+
+func newConventionResult(result *model.CheckResult) ConventionResult {
+	converted := ConventionResult{
+		Convention: result.Convention,
+		Directory:  result.Directory,
+		Module:     result.Module,
+		Passed:     !result.HasFailures(),
+		Summary: Summary{
+			Passed:     result.PassCount,
+			Failed:     result.FailCount,
+			Warnings:   result.WarnCount,
+			Skipped:    result.SkipCount,
+			Suppressed: result.SuppressedCount,
+		},
+		Rules: make([]RuleResult, 0, len(result.Results)),
+	}
+
+	for _, r := range result.Results {
+		rule := RuleResult{
+			ID:       r.Rule.ID,
+			Level:    r.Rule.Level.String(),
+			Type:     r.Rule.Type.String(),
+			Value:    r.Rule.Value,
+			Contains: r.Rule.Contains,
+			Matches:  r.Rule.Matches,
+			Exclude:  r.Rule.Exclude,
+			Each:     r.Rule.Each,
+			Status:   string(r.Status()),
+			Passed:   r.Passed,
+			Message:  r.Message,
+			Line:     r.Line,
+			Paths:    r.Paths,
+
+			Alternatives: r.Rule.Alternatives,
+			Description:  r.Rule.Description,
+			Rationale:    r.Rule.Rationale,
+			Remediation:  r.Rule.Remediation,
+		}
+		if c := r.Rule.When; c != nil {
+			rule.When = &Condition{Type: c.Type.String(), Value: c.Value, Contains: c.Contains, Matches: c.Matches}
+		}
+		if s := r.Suppression; s != nil {
+			rule.Suppression = &Suppression{Reason: s.Reason, Source: s.Source}
+			if !s.Expires.IsZero() {
+				rule.Suppression.Expires = s.Expires.Format(time.DateOnly)
+			}
+		}
+		converted.Rules = append(converted.Rules, rule)
+	}
+
+	return converted
+}
+
+func (s *Summary) add(other Summary) {
+	s.Passed += other.Passed
+	s.Failed += other.Failed
+	s.Warnings += other.Warnings
+	s.Skipped += other.Skipped
+	s.Suppressed += other.Suppressed
+}
+
+func writeJSON(w io.Writer, doc any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(doc)
+}
+
+func writeYAML(w io.Writer, doc any) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
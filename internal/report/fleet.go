@@ -0,0 +1,194 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/config"
+	"github.com/jimschubert/ossify/internal/fleet"
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+// worstOffenderLimit is the number of repositories listed as worst offenders
+const worstOffenderLimit = 10
+
+// FleetDocument is the versioned schema written by the json and yaml formats when many repositories are checked
+type FleetDocument struct {
+	Version int    `json:"version" yaml:"version"`
+	Kind    string `json:"kind" yaml:"kind"`
+	Tool    Tool   `json:"tool" yaml:"tool"`
+	Passed  bool   `json:"passed" yaml:"passed"`
+	// Summary counts rule outcomes across every repository
+	Summary      Summary           `json:"summary" yaml:"summary"`
+	Repositories []FleetRepository `json:"repositories" yaml:"repositories"`
+	// Rules are the checked rules, lowest pass rate first
+	Rules []FleetRule `json:"rules" yaml:"rules"`
+	// WorstOffenders are the directories of the failing repositories with the most failures
+	WorstOffenders []string `json:"worstOffenders" yaml:"worstOffenders"`
+}
+
+// FleetRepository is the outcome of checking one repository
+type FleetRepository struct {
+	Directory string             `json:"directory" yaml:"directory"`
+	Passed    bool               `json:"passed" yaml:"passed"`
+	Error     string             `json:"error,omitempty" yaml:"error,omitempty"`
+	Summary   Summary            `json:"summary" yaml:"summary"`
+	Results   []ConventionResult `json:"results" yaml:"results"`
+}
+
+// FleetRule is how many repositories pass a rule of a convention
+type FleetRule struct {
+	Convention string `json:"convention" yaml:"convention"`
+	ID         string `json:"id,omitempty" yaml:"id,omitempty"`
+	Level      string `json:"level" yaml:"level"`
+	Type       string `json:"type" yaml:"type"`
+	Value      string `json:"value" yaml:"value"`
+	Contains   string `json:"contains,omitempty" yaml:"contains,omitempty"`
+	Matches    string `json:"matches,omitempty" yaml:"matches,omitempty"`
+	Each       string `json:"each,omitempty" yaml:"each,omitempty"`
+	// Exclude are the paths removed from the matches of a pattern rule
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// Alternatives are accepted in place of Value
+	Alternatives []string `json:"alternatives,omitempty" yaml:"alternatives,omitempty"`
+	// When is the condition under which the rule applies
+	When     *Condition `json:"when,omitempty" yaml:"when,omitempty"`
+	Checked  int        `json:"checked" yaml:"checked"`
+	Passed   int        `json:"passed" yaml:"passed"`
+	PassRate float64    `json:"passRate" yaml:"passRate"`
+}
+
+// NewFleetDocument converts the results of many repositories into the versioned fleet schema
+func NewFleetDocument(repos []fleet.Repository) *FleetDocument {
+	doc := &FleetDocument{
+		Version:        SchemaVersion,
+		Kind:           "fleet",
+		Tool:           Tool{Name: "ossify", Version: config.Version},
+		Passed:         true,
+		Repositories:   make([]FleetRepository, 0, len(repos)),
+		Rules:          make([]FleetRule, 0),
+		WorstOffenders: make([]string, 0),
+	}
+
+	for _, repo := range repos {
+		converted := FleetRepository{
+			Directory: repo.Directory,
+			Passed:    !repo.Failed(),
+			Results:   make([]ConventionResult, 0, len(repo.Results)),
+		}
+		if repo.Err != nil {
+			converted.Error = repo.Err.Error()
+		}
+		for _, result := range repo.Results {
+			r := newConventionResult(result)
+			converted.Results = append(converted.Results, r)
+			converted.Summary.add(r.Summary)
+		}
+		doc.Repositories = append(doc.Repositories, converted)
+		doc.Summary.add(converted.Summary)
+		if !converted.Passed {
+			doc.Passed = false
+		}
+	}
+
+	for _, stat := range fleet.RuleStats(repos) {
+		rule := FleetRule{
+			Convention:   stat.Convention,
+			ID:           stat.Rule.ID,
+			Level:        stat.Rule.Level.String(),
+			Type:         stat.Rule.Type.String(),
+			Value:        stat.Rule.Value,
+			Contains:     stat.Rule.Contains,
+			Matches:      stat.Rule.Matches,
+			Each:         stat.Rule.Each,
+			Exclude:      stat.Rule.Exclude,
+			Alternatives: stat.Rule.Alternatives,
+			Checked:      stat.Checked,
+			Passed:       stat.Passed,
+			// rounded so that serialized rates stay readable
+			PassRate: math.Round(stat.PassRate()*1000) / 1000,
+		}
+		if c := stat.Rule.When; c != nil {
+			rule.When = &Condition{Type: c.Type.String(), Value: c.Value, Contains: c.Contains, Matches: c.Matches}
+		}
+		doc.Rules = append(doc.Rules, rule)
+	}
+
+	for _, repo := range fleet.WorstOffenders(repos, worstOffenderLimit) {
+		doc.WorstOffenders = append(doc.WorstOffenders, repo.Directory)
+	}
+	return doc
+}
+
+// WriteFleet serializes the results of many repositories to w. Text, json and yaml describe the whole fleet,
+// with the pass rate of each rule and the worst offenders; the other formats combine the results of every
+// repository which could be checked.
+func WriteFleet(w io.Writer, format Format, repos []fleet.Repository) error {
+	switch format {
+	case Text:
+		return writeFleetText(w, repos)
+	case JSON:
+		return writeJSON(w, NewFleetDocument(repos))
+	case YAML:
+		return writeYAML(w, NewFleetDocument(repos))
+	default:
+		var results []*model.CheckResult
+		for _, repo := range repos {
+			results = append(results, repo.Results...)
+		}
+		return Write(w, format, results)
+	}
+}
+
+func writeFleetText(w io.Writer, repos []fleet.Repository) error {
+	var b strings.Builder
+
+	failing := 0
+	for _, repo := range repos {
+		if repo.Failed() {
+			failing++
+		}
+	}
+	_, _ = fmt.Fprintf(&b, "Checked %d repositories: %d passed, %d failed\n\n", len(repos), len(repos)-failing, failing)
+
+	b.WriteString("Repositories:\n")
+	for _, repo := range repos {
+		if repo.Failed() {
+			_, _ = fmt.Fprintf(&b, "  ✗ %s (%s)\n", repo.Directory, describeFailure(repo))
+		} else {
+			_, _ = fmt.Fprintf(&b, "  ✓ %s\n", repo.Directory)
+		}
+	}
+
+	if stats := fleet.RuleStats(repos); len(stats) > 0 {
+		b.WriteString("\nRules by pass rate:\n")
+		for _, stat := range stats {
+			name := ruleName(stat.Rule)
+			if stat.Rule.When != nil {
+				name += " when " + stat.Rule.When.String()
+			}
+			_, _ = fmt.Fprintf(&b, "  %4.0f%%  %s: %s (%d/%d)\n",
+				stat.PassRate()*100, stat.Convention, name, stat.Passed, stat.Checked)
+		}
+	}
+
+	if offenders := fleet.WorstOffenders(repos, worstOffenderLimit); len(offenders) > 0 {
+		b.WriteString("\nWorst offenders:\n")
+		for i, repo := range offenders {
+			_, _ = fmt.Fprintf(&b, "  %2d. %s (%s)\n", i+1, repo.Directory, describeFailure(repo))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// describeFailure summarizes why a repository failed, e.g. "3 failed, 1 warnings"
+func describeFailure(repo fleet.Repository) string {
+	if repo.Err != nil {
+		return "error: " + repo.Err.Error()
+	}
+	failed, warnings := repo.Count()
+	return fmt.Sprintf("%d failed, %d warnings", failed, warnings)
+}
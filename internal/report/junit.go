@@ -0,0 +1,86 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	// Text explains the failed rule, when the convention does
+	Text string `xml:",chardata"`
+}
+
+// writeJUnit writes one test suite per convention and one test case per rule.
+// JUnit has no notion of warnings, so warnings are passing test cases with the message in system-out,
+// and suppressed failures are skipped.
+func writeJUnit(w io.Writer, results []*model.CheckResult) error {
+	suites := junitTestSuites{Name: "ossify"}
+
+	for _, result := range results {
+		suite := junitTestSuite{Name: resultName(result)}
+		for _, r := range result.Results {
+			testCase := junitTestCase{Name: ruleName(r.Rule), ClassName: suite.Name}
+			switch r.Status() {
+			case model.StatusFail:
+				testCase.Failure = &junitMessage{Message: r.Message, Type: r.Rule.Level.String(), Text: strings.Join(r.Rule.Guidance(), "\n")}
+				suite.Failures++
+			case model.StatusSkip:
+				testCase.Skipped = &junitMessage{Message: r.Message}
+				suite.Skipped++
+			case model.StatusSuppressed:
+				testCase.Skipped = &junitMessage{Message: "suppressed: " + r.Suppression.Reason}
+				suite.Skipped++
+			case model.StatusWarn:
+				testCase.SystemOut = strings.Join(append([]string{"warning: " + r.Message}, r.Rule.Guidance()...), "\n")
+			}
+			suite.Cases = append(suite.Cases, testCase)
+			suite.Tests++
+		}
+
+		suites.Suites = append(suites.Suites, suite)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
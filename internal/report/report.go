@@ -0,0 +1,135 @@
+// Package report serializes convention check results into human and machine-readable formats.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+// SchemaVersion is the version of the structured (json and yaml) report document.
+// It is incremented whenever a field is removed or changes meaning; new fields may be added within a version.
+const SchemaVersion = 1
+
+// Format identifies an output format for check results
+type Format string
+
+const (
+	Text  Format = "text"
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+	JUnit Format = "junit"
+	TAP   Format = "tap"
+	SARIF Format = "sarif"
+)
+
+var formats = []Format{Text, JSON, YAML, JUnit, TAP, SARIF}
+
+// Formats lists the names of all supported formats
+func Formats() []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// ParseFormat validates a format name (case-insensitive)
+func ParseFormat(name string) (Format, error) {
+	for _, f := range formats {
+		if strings.EqualFold(string(f), name) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q: expected one of %s", name, strings.Join(Formats(), ", "))
+}
+
+// Write serializes results to w in the given format
+func Write(w io.Writer, format Format, results []*model.CheckResult) error {
+	switch format {
+	case Text:
+		return writeText(w, results)
+	case JSON:
+		return writeJSON(w, NewDocument(results))
+	case YAML:
+		return writeYAML(w, NewDocument(results))
+	case JUnit:
+		return writeJUnit(w, results)
+	case TAP:
+		return writeTAP(w, results)
+	case SARIF:
+		return writeSARIF(w, results)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// separatorWidth is the width of the line printed between conventions in text output
+const separatorWidth = 60
+
+func writeText(w io.Writer, results []*model.CheckResult) error {
+	for i, result := range results {
+		if i > 0 {
+			if _, err := fmt.Fprint(w, "\n"+strings.Repeat("-", separatorWidth)+"\n\n"); err != nil {
+				return err
+			}
+		}
+		result.Fprint(w)
+	}
+	return writeModuleSummary(w, results)
+}
+
+// writeModuleSummary lists the modules of a recursive check and whether each passed
+func writeModuleSummary(w io.Writer, results []*model.CheckResult) error {
+	var modules []string
+	failed := make(map[string]bool)
+	for _, result := range results {
+		if result.Module == "" {
+			continue
+		}
+		if _, seen := failed[result.Module]; !seen {
+			modules = append(modules, result.Module)
+			failed[result.Module] = false
+		}
+		if result.HasFailures() {
+			failed[result.Module] = true
+		}
+	}
+	if len(modules) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	failing := 0
+	b.WriteString("\n" + strings.Repeat("=", separatorWidth) + "\n\n")
+	for _, module := range modules {
+		status := "✓"
+		if failed[module] {
+			status = "✗"
+			failing++
+		}
+		_, _ = fmt.Fprintf(&b, "  %s %s\n", status, module)
+	}
+	_, _ = fmt.Fprintf(&b, "\nModules: %d checked, %d passed, %d failed\n", len(modules), len(modules)-failing, failing)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// resultName names the result of a convention, qualified by its module in a recursive check
+func resultName(result *model.CheckResult) string {
+	if result.Module == "" {
+		return result.Convention
+	}
+	return fmt.Sprintf("%s (%s)", result.Convention, result.Module)
+}
+
+// ruleName is the identifier used for a rule in formats which name each check, such as junit and tap,
+// prefixed by the rule's ID when it has one
+func ruleName(rule model.Rule) string {
+	if rule.ID != "" {
+		return fmt.Sprintf("%s: %s %s %s", rule.ID, rule.Level, rule.Type, rule.Value)
+	}
+	return fmt.Sprintf("%s %s %s", rule.Level, rule.Type, rule.Value)
+}
@@ -0,0 +1,465 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jimschubert/ossify/internal/fleet"
+	"github.com/jimschubert/ossify/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+func testResults() []*model.CheckResult {
+	return []*model.CheckResult{
+		{
+			Convention: "Go",
+			Directory:  "/tmp/project",
+			Results: []model.RuleResult{
+				{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "README.md"}, Passed: true, Message: "found"},
+				{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "LICENSE"}, Passed: false, Message: "missing"},
+				{Rule: model.Rule{Level: model.Preferred, Type: model.Directory, Value: "docs"}, Passed: false, Message: "recommended but missing"},
+				{Rule: model.Rule{Level: model.Prohibited, Type: model.Content, Value: "NOTICE", Contains: "<OWNER>"}, Passed: false, Message: "prohibited content found at line 1", Line: 1},
+			},
+			PassCount: 1,
+			FailCount: 2,
+			WarnCount: 1,
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{"text", Text, false},
+		{"JSON", JSON, false},
+		{"yaml", YAML, false},
+		{"junit", JUnit, false},
+		{"tap", TAP, false},
+		{"csv", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite_Structured(t *testing.T) {
+	tests := []struct {
+		format    Format
+		unmarshal func([]byte, any) error
+	}{
+		{JSON, json.Unmarshal},
+		{YAML, yaml.Unmarshal},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.format, testResults()); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			var doc Document
+			if err := tt.unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("failed to parse output: %v\n%s", err, buf.String())
+			}
+
+			if doc.Version != SchemaVersion {
+				t.Errorf("Version = %d, want %d", doc.Version, SchemaVersion)
+			}
+			if doc.Passed {
+				t.Errorf("Passed = true, want false")
+			}
+			if doc.Summary != (Summary{Passed: 1, Failed: 2, Warnings: 1}) {
+				t.Errorf("Summary = %+v", doc.Summary)
+			}
+			if len(doc.Results) != 1 || len(doc.Results[0].Rules) != 4 {
+				t.Fatalf("unexpected results: %+v", doc.Results)
+			}
+
+			rule := doc.Results[0].Rules[3]
+			if rule.Status != "fail" || rule.Line != 1 || rule.Contains != "<OWNER>" || rule.Level != "prohibited" || rule.Type != "content" {
+				t.Errorf("unexpected content rule result: %+v", rule)
+			}
+			if status := doc.Results[0].Rules[2].Status; status != "warn" {
+				t.Errorf("preferred rule status = %s, want warn", status)
+			}
+		})
+	}
+}
+
+func TestWrite_JUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, JUnit, testResults()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if suites.Tests != 4 || suites.Failures != 2 {
+		t.Errorf("tests = %d, failures = %d, want 4 and 2", suites.Tests, suites.Failures)
+	}
+	if got := suites.Suites[0].Cases[1].Failure; got == nil || got.Message != "missing" {
+		t.Errorf("expected failure for LICENSE, got %+v", got)
+	}
+}
+
+func TestWrite_TAP(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, TAP, testResults()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"TAP version 13\n1..4\n",
+		"ok 1 - Go: required file README.md\n",
+		"not ok 2 - Go: required file LICENSE\n",
+		"not ok 3 - Go: preferred directory docs # TODO recommended but missing\n",
+		"  line: 1\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TAP output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestWrite_Suppressed(t *testing.T) {
+	results := testResults()
+	results[0].Results[1].Suppression = &model.Suppression{Convention: "Go", Value: "LICENSE", Reason: "license is added at release", Source: ".ossifyignore:1"}
+	results[0].FailCount, results[0].SuppressedCount = 1, 1
+
+	var buf bytes.Buffer
+	if err := Write(&buf, JSON, results); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var doc Document
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	rule := doc.Results[0].Rules[1]
+	if rule.Status != "suppressed" || rule.Suppression == nil || rule.Suppression.Source != ".ossifyignore:1" || doc.Summary.Suppressed != 1 {
+		t.Errorf("unexpected suppressed rule %+v with summary %+v", rule, doc.Summary)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, TAP, results); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if want := "ok 2 - Go: required file LICENSE # SKIP suppressed: license is added at release\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("TAP output missing %q:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := Write(&buf, SARIF, results); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	suppressions := log.Runs[0].Results[0].Suppressions
+	if len(suppressions) != 1 || suppressions[0].Kind != "external" || suppressions[0].Justification != "license is added at release" {
+		t.Errorf("unexpected SARIF suppressions %+v", suppressions)
+	}
+}
+
+func TestWrite_Guidance(t *testing.T) {
+	results := testResults()
+	results[0].Results[1].Rule.ID = "license-present"
+	results[0].Results[1].Rule.Description = "Every repository has a license"
+	results[0].Results[1].Rule.Rationale = "https://opensource.guide/legal/"
+	results[0].Results[1].Rule.Remediation = "run `ossify license MIT > LICENSE`"
+
+	outputs := make(map[Format]string)
+	for _, format := range formats {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, results); err != nil {
+			t.Fatalf("Write(%s) error = %v", format, err)
+		}
+		outputs[format] = buf.String()
+	}
+
+	for _, format := range []Format{Text, JSON, YAML, JUnit, TAP, SARIF} {
+		for _, want := range []string{"license-present", "Every repository has a license", "https://opensource.guide/legal/", "ossify license MIT"} {
+			if !strings.Contains(outputs[format], want) {
+				t.Errorf("%s output missing %q:\n%s", format, want, outputs[format])
+			}
+		}
+	}
+	if want := "      fix: run `ossify license MIT > LICENSE`\n"; !strings.Contains(outputs[Text], want) {
+		t.Errorf("text output missing %q:\n%s", want, outputs[Text])
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(outputs[SARIF]), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	descriptor := log.Runs[0].Tool.Driver.Rules[1]
+	if descriptor.ID != "license-present" || descriptor.HelpURI != "https://opensource.guide/legal/" || descriptor.Help == nil || log.Runs[0].Results[0].RuleID != "license-present" {
+		t.Errorf("unexpected SARIF rule %+v", descriptor)
+	}
+
+	var doc Document
+	if err := json.Unmarshal([]byte(outputs[JSON]), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if rule := doc.Results[0].Rules[1]; rule.ID != "license-present" || rule.Remediation == "" {
+		t.Errorf("unexpected rule %+v", rule)
+	}
+	if rule := doc.Results[0].Rules[0]; rule.ID != "" || rule.Description != "" {
+		t.Errorf("rule without guidance = %+v", rule)
+	}
+}
+
+func TestWrite_Conditional(t *testing.T) {
+	results := testResults()
+	results[0].Results[2].Rule.When = &model.Condition{Type: model.File, Value: "mkdocs.yml"}
+	results[0].Results[2].Skipped, results[0].Results[2].Message = true, "condition not met"
+	results[0].Recount()
+
+	outputs := make(map[Format]string)
+	for _, format := range formats {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, results); err != nil {
+			t.Fatalf("Write(%s) error = %v", format, err)
+		}
+		outputs[format] = buf.String()
+	}
+
+	if want := "ok 3 - Go: preferred directory docs # SKIP condition not met\n"; !strings.Contains(outputs[TAP], want) {
+		t.Errorf("TAP output missing %q:\n%s", want, outputs[TAP])
+	}
+
+	var doc Document
+	if err := json.Unmarshal([]byte(outputs[JSON]), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	rule := doc.Results[0].Rules[2]
+	if rule.Status != "skip" || rule.When == nil || rule.When.Type != "file" || rule.When.Value != "mkdocs.yml" {
+		t.Errorf("unexpected rule %+v", rule)
+	}
+	if doc.Summary.Skipped != 1 || doc.Summary.Warnings != 0 {
+		t.Errorf("summary = %+v, want 1 skipped and no warnings", doc.Summary)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(outputs[SARIF]), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	for _, r := range log.Runs[0].Results {
+		if r.RuleIndex == 2 {
+			t.Errorf("skipped rule reported as a SARIF result: %+v", r)
+		}
+	}
+	if got := log.Runs[0].Tool.Driver.Rules[2].ShortDescription.Text; got != "Preferred directory: docs when file mkdocs.yml exists" {
+		t.Errorf("SARIF rule description = %q", got)
+	}
+}
+
+func TestWrite_Modules(t *testing.T) {
+	results := testResults()
+	results[0].Module = "services/api"
+
+	var buf bytes.Buffer
+	if err := Write(&buf, Text, results); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	for _, want := range []string{"against module services/api:", "✗ services/api", "Modules: 1 checked, 0 passed, 1 failed"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := Write(&buf, JUnit, results); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if want := `<testsuite name="Go (services/api)"`; !strings.Contains(buf.String(), want) {
+		t.Errorf("JUnit output missing %q:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := Write(&buf, SARIF, results); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if id := log.Runs[0].AutomationDetails.ID; id != "Go/services/api/" {
+		t.Errorf("SARIF automation id = %q", id)
+	}
+}
+
+func TestWrite_SARIF(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "NOTICE"), []byte("Copyright <OWNER>\n"), 0644); err != nil {
+		t.Fatalf("failed to write NOTICE: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatalf("failed to create src: %v", err)
+	}
+
+	results := testResults()
+	results[0].Directory = dir
+	results[0].Results = append(results[0].Results,
+		model.RuleResult{Rule: model.Rule{Level: model.Prohibited, Type: model.Directory, Value: "src"}, Passed: false, Message: "prohibited directory exists"},
+		model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "docs/index.md"}, Passed: false, Message: "missing"},
+	)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, SARIF, results); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: version %s, %d runs", log.Version, len(log.Runs))
+	}
+
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 6 {
+		t.Errorf("rules = %d, want one descriptor per rule (6)", len(run.Tool.Driver.Rules))
+	}
+
+	type want struct {
+		level  string
+		uri    string
+		region int
+	}
+	wants := []want{
+		{"error", "", 0},       // LICENSE missing
+		{"warning", "", 0},     // docs preferred, missing
+		{"error", "NOTICE", 1}, // prohibited content in NOTICE
+		{"error", "src/", 0},   // prohibited directory
+		{"error", "docs/", 0},  // missing file expected in docs/
+	}
+	if len(run.Results) != len(wants) {
+		t.Fatalf("results = %d, want %d", len(run.Results), len(wants))
+	}
+	for i, w := range wants {
+		got := run.Results[i]
+		loc := got.Locations[0].PhysicalLocation
+		if got.Level != w.level || loc.ArtifactLocation.URI != w.uri {
+			t.Errorf("result %d = level %s uri %q, want level %s uri %q", i, got.Level, loc.ArtifactLocation.URI, w.level, w.uri)
+		}
+		if (loc.Region == nil && w.region != 0) || (loc.Region != nil && loc.Region.StartLine != w.region) {
+			t.Errorf("result %d region = %+v, want start line %d", i, loc.Region, w.region)
+		}
+		if run.Tool.Driver.Rules[got.RuleIndex].ID != got.RuleID {
+			t.Errorf("result %d ruleIndex %d does not reference rule %s", i, got.RuleIndex, got.RuleID)
+		}
+	}
+}
+
+func TestWrite_SARIF_UniqueRules(t *testing.T) {
+	results := testResults()
+	results[0].Results = append(results[0].Results,
+		model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.Content, Value: "README.md", Contains: "Usage"}, Message: "missing"},
+		model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.Content, Value: "README.md", Contains: "License"}, Message: "missing"},
+		model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "SECURITY.md", ID: "security"}, Message: "missing"},
+		model.RuleResult{Rule: model.Rule{Level: model.Required, Type: model.File, Value: "SECURITY.rst", ID: "security"}, Message: "missing"},
+	)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, SARIF, results); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+
+	run := log.Runs[0]
+	seen := make(map[string]bool)
+	for _, rule := range run.Tool.Driver.Rules {
+		if seen[rule.ID] {
+			t.Errorf("rule id %s is not unique", rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+	// both content rules are described, while the rules sharing an id share a descriptor
+	if len(run.Tool.Driver.Rules) != 7 {
+		t.Errorf("rules = %d, want 7", len(run.Tool.Driver.Rules))
+	}
+	for i, r := range run.Results {
+		if run.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+			t.Errorf("result %d ruleIndex %d does not reference rule %s", i, r.RuleIndex, r.RuleID)
+		}
+	}
+}
+
+func TestWriteFleet(t *testing.T) {
+	passing := testResults()
+	passing[0].Directory = "/tmp/other"
+	for i := range passing[0].Results {
+		passing[0].Results[i].Passed = true
+	}
+	passing[0].Recount()
+
+	repos := []fleet.Repository{
+		{Directory: "/tmp/project", Results: testResults()},
+		{Directory: "/tmp/other", Results: passing},
+		{Directory: "/tmp/missing", Err: errors.New("no such file or directory")},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFleet(&buf, JSON, repos); err != nil {
+		t.Fatalf("WriteFleet() error = %v", err)
+	}
+	var doc FleetDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Kind != "fleet" || doc.Passed || len(doc.Repositories) != 3 || doc.Repositories[2].Error == "" {
+		t.Errorf("unexpected fleet document %+v", doc)
+	}
+	if rule := doc.Rules[0]; rule.Passed != 1 || rule.Checked != 2 || rule.PassRate != 0.5 {
+		t.Errorf("unexpected lowest pass rate rule %+v", rule)
+	}
+	if want := []string{"/tmp/missing", "/tmp/project"}; strings.Join(doc.WorstOffenders, ",") != strings.Join(want, ",") {
+		t.Errorf("worst offenders = %v, want %v", doc.WorstOffenders, want)
+	}
+
+	buf.Reset()
+	if err := WriteFleet(&buf, Text, repos); err != nil {
+		t.Fatalf("WriteFleet() error = %v", err)
+	}
+	for _, want := range []string{"Checked 3 repositories: 1 passed, 2 failed", "50%  Go: required file LICENSE (1/2)", " 1. /tmp/missing (error: no such file or directory)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := WriteFleet(&buf, JUnit, repos); err != nil {
+		t.Fatalf("WriteFleet() error = %v", err)
+	}
+	if got := strings.Count(buf.String(), "<testsuite "); got != 2 {
+		t.Errorf("expected a JUnit suite per checked repository, got %d", got)
+	}
+}
@@ -0,0 +1,331 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/config"
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifRootBaseID is the uriBaseId against which all result locations are resolved
+	sarifRootBaseID = "SRCROOT"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	AutomationDetails  sarifAutomationDetails      `json:"automationDetails"`
+	OriginalURIBaseIDs map[string]sarifArtifactLoc `json:"originalUriBaseIds"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                `json:"name"`
+	Version        string                `json:"version"`
+	InformationURI string                `json:"informationUri"`
+	Rules          []sarifReportingDescr `json:"rules"`
+}
+
+type sarifAutomationDetails struct {
+	ID string `json:"id"`
+}
+
+type sarifReportingDescr struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      *sarifMessage      `json:"fullDescription,omitempty"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	Help                 *sarifMessage      `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]string  `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLoc `json:"artifactLocation"`
+	Region           *sarifRegion     `json:"region,omitempty"`
+}
+
+type sarifArtifactLoc struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+var sarifIDUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// writeSARIF writes a SARIF 2.1.0 log with one run per checked convention. Each rule of the convention is
+// a reporting descriptor, shared by rules with the same ID, and each failing rule (including warnings) is a
+// result; suppressed failures are results carrying a suppression so that dashboards can show them as waived.
+func writeSARIF(w io.Writer, results []*model.CheckResult) error {
+	log := sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: make([]sarifRun, 0, len(results))}
+
+	for _, result := range results {
+		run := sarifRun{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "ossify",
+				Version:        config.Version,
+				InformationURI: "https://github.com/jimschubert/ossify",
+				Rules:          make([]sarifReportingDescr, 0, len(result.Results)),
+			}},
+			AutomationDetails: sarifAutomationDetails{ID: sarifAutomationID(result)},
+			OriginalURIBaseIDs: map[string]sarifArtifactLoc{
+				sarifRootBaseID: {URI: directoryURI(result.Directory)},
+			},
+			Results: make([]sarifResult, 0),
+		}
+
+		// descriptors maps each rule ID to its descriptor, as IDs must be unique within a run
+		descriptors := make(map[string]int, len(result.Results))
+		for _, r := range result.Results {
+			id := sarifRuleID(result.Convention, r.Rule)
+			index, ok := descriptors[id]
+			if !ok {
+				index = len(run.Tool.Driver.Rules)
+				descriptors[id] = index
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifDescriptor(id, result.Convention, r.Rule))
+			}
+
+			if r.Passed || r.Skipped {
+				continue
+			}
+
+			sr := sarifResult{
+				RuleID:    id,
+				RuleIndex: index,
+				Level:     sarifLevel(r.Rule.Level),
+				Message:   sarifMessage{Text: fmt.Sprintf("%s: %s", r.Rule.Value, r.Message)},
+				Locations: sarifResultLocations(result.Directory, r),
+			}
+			if r.Suppression != nil {
+				// suppressions declared outside the analyzed artifacts are "external"
+				sr.Suppressions = []sarifSuppression{{Kind: "external", Justification: r.Suppression.Reason}}
+			}
+			run.Results = append(run.Results, sr)
+		}
+
+		log.Runs = append(log.Runs, run)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(log)
+}
+
+// sarifDescriptor describes a rule of convention, identified by id
+func sarifDescriptor(id, convention string, rule model.Rule) sarifReportingDescr {
+	descriptor := sarifReportingDescr{
+		ID:                   id,
+		Name:                 ruleName(rule),
+		ShortDescription:     sarifMessage{Text: describeRule(rule)},
+		HelpURI:              rule.Rationale,
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Level)},
+		Properties:           map[string]string{"convention": convention},
+	}
+	if rule.Description != "" {
+		descriptor.FullDescription = &sarifMessage{Text: rule.Description}
+	}
+	if rule.Remediation != "" {
+		descriptor.Help = &sarifMessage{Text: rule.Remediation}
+	}
+	return descriptor
+}
+
+// sarifLevel maps a strictness level to a SARIF result level
+func sarifLevel(level model.StrictnessLevel) string {
+	switch level {
+	case model.Required, model.Prohibited:
+		return "error"
+	case model.Preferred:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifRuleID builds an identifier which is stable for a given convention and rule: the rule's own ID when it
+// has one, so that renaming the convention or changing the rule does not lose its history. Rules on the same
+// value which check different things, e.g. several content rules on README.md, are told apart by a hash of
+// what they check.
+func sarifRuleID(convention string, rule model.Rule) string {
+	if rule.ID != "" {
+		return rule.ID
+	}
+	parts := []string{convention, rule.Level.String(), rule.Type.String(), rule.Value}
+	for i, p := range parts {
+		parts[i] = strings.Trim(sarifIDUnsafe.ReplaceAllString(p, "-"), "-")
+	}
+
+	checks := []string{rule.Contains, rule.Matches, rule.Each, strings.Join(rule.Exclude, ","), strings.Join(rule.Alternatives, ",")}
+	if rule.When != nil {
+		checks = append(checks, rule.When.String())
+	}
+	if key := strings.Join(checks, "\x00"); strings.Trim(key, "\x00") != "" {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		parts = append(parts, fmt.Sprintf("%08x", h.Sum32()))
+	}
+	return strings.Join(parts, "/")
+}
+
+// sarifAutomationID identifies the run of a convention, and its module in a recursive check, so that code
+// scanning tracks each run separately
+func sarifAutomationID(result *model.CheckResult) string {
+	if result.Module == "" {
+		return result.Convention + "/"
+	}
+	return result.Convention + "/" + result.Module + "/"
+}
+
+// sarifResultLocations points at each path reported by a pattern rule, or otherwise at the single location
+// described by sarifResultLocation
+func sarifResultLocations(directory string, r model.RuleResult) []sarifLocation {
+	if len(r.Paths) == 0 {
+		return []sarifLocation{sarifResultLocation(directory, r)}
+	}
+
+	locations := make([]sarifLocation, 0, len(r.Paths))
+	for _, p := range r.Paths {
+		uri := p
+		if info, err := os.Stat(filepath.Join(directory, filepath.FromSlash(p))); err == nil && info.IsDir() {
+			uri = directoryOf(p)
+		}
+		locations = append(locations, sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLoc{URI: uri, URIBaseID: sarifRootBaseID},
+		}})
+	}
+	return locations
+}
+
+// sarifResultLocation points at the offending item when it exists (a prohibited item, or a file whose content
+// failed), and otherwise at the directory in which a missing item was expected. Pattern rules point at the
+// deepest directory of the pattern without wildcards.
+func sarifResultLocation(directory string, r model.RuleResult) sarifLocation {
+	value := filepath.ToSlash(r.Rule.Value)
+	uri := value
+
+	if r.Rule.Type == model.Pattern {
+		uri = directoryOf(globBase(value))
+	} else if info, err := os.Stat(filepath.Join(directory, r.Rule.Value)); err != nil {
+		uri = directoryOf(path.Dir(value))
+	} else if info.IsDir() {
+		uri = directoryOf(value)
+	}
+
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLoc{URI: uri, URIBaseID: sarifRootBaseID},
+	}}
+	if r.Line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: r.Line}
+	}
+	return loc
+}
+
+// directoryOf formats a slash-separated directory as a relative URI with a trailing slash;
+// the root of the checked directory is the empty URI.
+func directoryOf(dir string) string {
+	if dir == "." || dir == "" {
+		return ""
+	}
+	return strings.TrimSuffix(dir, "/") + "/"
+}
+
+// globBase returns the leading path segments of pattern which contain no glob metacharacters
+func globBase(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	base := make([]string, 0, len(segments))
+	for _, segment := range segments[:len(segments)-1] {
+		if strings.ContainsAny(segment, "*?[{") {
+			break
+		}
+		base = append(base, segment)
+	}
+	return strings.Join(base, "/")
+}
+
+// directoryURI converts an absolute directory to a file URI with a trailing slash, as SARIF requires for base URIs
+func directoryURI(dir string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}
+	s := u.String()
+	if !strings.HasSuffix(s, "/") {
+		s += "/"
+	}
+	return s
+}
+
+// describeRule is a sentence describing what a rule expects
+func describeRule(rule model.Rule) string {
+	var description string
+	switch rule.Type {
+	case model.Content:
+		if rule.Matches != "" {
+			description = fmt.Sprintf("%s content in %s matching %q", capitalize(rule.Level.String()), rule.Value, rule.Matches)
+		} else {
+			description = fmt.Sprintf("%s content in %s containing %q", capitalize(rule.Level.String()), rule.Value, rule.Contains)
+		}
+	default:
+		description = fmt.Sprintf("%s %s: %s", capitalize(rule.Level.String()), rule.Type, strings.Join(rule.Values(), " or "))
+	}
+	if rule.When != nil {
+		description += " when " + rule.When.String()
+	}
+	return description
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
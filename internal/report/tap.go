@@ -0,0 +1,81 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jimschubert/ossify/internal/model"
+)
+
+// writeTAP writes results as TAP version 13. Warnings are reported as "not ok" with a TODO directive
+// and skipped rules and suppressed failures with a SKIP directive, so that none is counted as a failure by
+// TAP consumers.
+func writeTAP(w io.Writer, results []*model.CheckResult) error {
+	var b strings.Builder
+
+	total := 0
+	for _, result := range results {
+		total += len(result.Results)
+	}
+
+	b.WriteString("TAP version 13\n")
+	_, _ = fmt.Fprintf(&b, "1..%d\n", total)
+
+	n := 0
+	for _, result := range results {
+		_, _ = fmt.Fprintf(&b, "# %s (%s)\n", result.Convention, result.Directory)
+		for _, r := range result.Results {
+			n++
+			description := fmt.Sprintf("%s: %s", result.Convention, ruleName(r.Rule))
+			switch r.Status() {
+			case model.StatusPass:
+				_, _ = fmt.Fprintf(&b, "ok %d - %s\n", n, tapEscape(description))
+			case model.StatusSkip:
+				_, _ = fmt.Fprintf(&b, "ok %d - %s # SKIP %s\n", n, tapEscape(description), tapEscape(r.Message))
+			case model.StatusSuppressed:
+				_, _ = fmt.Fprintf(&b, "ok %d - %s # SKIP suppressed: %s\n", n, tapEscape(description), tapEscape(r.Suppression.Reason))
+			case model.StatusWarn:
+				_, _ = fmt.Fprintf(&b, "not ok %d - %s # TODO %s\n", n, tapEscape(description), tapEscape(r.Message))
+			default:
+				_, _ = fmt.Fprintf(&b, "not ok %d - %s\n", n, tapEscape(description))
+				writeTAPDiagnostics(&b, result, r)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTAPDiagnostics(b *strings.Builder, result *model.CheckResult, r model.RuleResult) {
+	b.WriteString("  ---\n")
+	_, _ = fmt.Fprintf(b, "  message: %q\n", r.Message)
+	_, _ = fmt.Fprintf(b, "  severity: %s\n", r.Status())
+	_, _ = fmt.Fprintf(b, "  convention: %q\n", result.Convention)
+	_, _ = fmt.Fprintf(b, "  directory: %q\n", result.Directory)
+	for _, field := range []struct{ name, value string }{
+		{"id", r.Rule.ID}, {"description", r.Rule.Description}, {"remediation", r.Rule.Remediation}, {"rationale", r.Rule.Rationale},
+	} {
+		if field.value != "" {
+			_, _ = fmt.Fprintf(b, "  %s: %q\n", field.name, field.value)
+		}
+	}
+	if r.Line > 0 {
+		_, _ = fmt.Fprintf(b, "  line: %d\n", r.Line)
+	}
+	if len(r.Paths) > 0 {
+		b.WriteString("  paths:\n")
+		for _, p := range r.Paths {
+			_, _ = fmt.Fprintf(b, "    - %q\n", p)
+		}
+	}
+	b.WriteString("  ...\n")
+}
+
+// tapEscape escapes characters which have meaning in a TAP test line
+func tapEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "#", "\\#")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Dialect is the JSON Schema version of generated documents
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema generated from Go types
+type Schema struct {
+	Schema     string             `json:"$schema,omitempty"`
+	Title      string             `json:"title,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is false for objects generated from structs, which allow only their fields
+	AdditionalProperties *bool    `json:"additionalProperties,omitempty"`
+	Items                *Schema  `json:"items,omitempty"`
+	Enum                 []string `json:"enum,omitempty"`
+}
+
+// Enumerated is implemented by types written as one of a fixed set of names, e.g. model.StrictnessLevel
+type Enumerated interface {
+	Enum() []string
+}
+
+var enumerated = reflect.TypeFor[Enumerated]()
+
+// Generate builds the schema of the JSON documents v is read from. Struct fields are named by their json tag,
+// fields tagged `jsonschema:"required"` are required and no other fields are allowed. Types implementing
+// Enumerated are strings restricted to their names.
+func Generate(v any) *Schema {
+	return generate(reflect.TypeOf(v))
+}
+
+// Document is the schema of v as a standalone document titled title, which may name its schema with "$schema"
+func Document(v any, title string) *Schema {
+	s := Generate(v)
+	s.Schema = Dialect
+	s.Title = title
+	if s.Properties != nil {
+		s.Properties["$schema"] = &Schema{Type: "string"}
+	}
+	return s
+}
+
+func generate(t reflect.Type) *Schema {
+	if t.Implements(enumerated) {
+		return &Schema{Type: "string", Enum: reflect.Zero(t).Interface().(Enumerated).Enum()}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return generate(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: generate(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		closed := false
+		s := &Schema{Title: t.Name(), Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: &closed}
+		for field := range fields(t) {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" {
+				name = field.Name
+			}
+			s.Properties[name] = generate(field.Type)
+			if slices.Contains(strings.Split(field.Tag.Get("jsonschema"), ","), "required") {
+				s.Required = append(s.Required, name)
+			}
+		}
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// fields yields the exported fields of t which are written to JSON
+func fields(t reflect.Type) iter.Seq[reflect.StructField] {
+	return func(yield func(reflect.StructField) bool) {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			if !yield(field) {
+				return
+			}
+		}
+	}
+}
+
+// Describe names the kind of value s accepts, e.g. "a string" or "an array of strings"
+func (s *Schema) Describe() string {
+	switch s.Type {
+	case "":
+		return "any value"
+	case "array":
+		if s.Items != nil && s.Items.Type != "" {
+			return "an array of " + strings.TrimPrefix(strings.TrimPrefix(s.Items.Describe(), "a "), "an ") + "s"
+		}
+		return "an array"
+	case "integer", "object":
+		return "an " + s.Type
+	default:
+		return "a " + s.Type
+	}
+}
+
+// Accepts reports whether a scalar JSON value, decoded by encoding/json with UseNumber, has the type s allows;
+// objects and arrays are left to the caller, which walks them
+func (s *Schema) Accepts(value any) bool {
+	switch s.Type {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		n, ok := value.(interface{ Int64() (int64, error) })
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case "number":
+		_, ok := value.(interface{ Float64() (float64, error) })
+		return ok
+	default:
+		return true
+	}
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type color int
+
+func (color) Enum() []string {
+	return []string{"red", "green"}
+}
+
+type palette struct {
+	Name    string   `json:"name" jsonschema:"required"`
+	Primary color    `json:"primary"`
+	Shades  []color  `json:"shades,omitempty"`
+	Weight  float64  `json:"weight,omitempty"`
+	Count   int      `json:"count,omitempty"`
+	Public  bool     `json:"public,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Nested  *palette `json:"-"`
+	hidden  string
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(palette{})
+
+	if s.Type != "object" || s.Title != "palette" || s.AdditionalProperties == nil || *s.AdditionalProperties {
+		t.Errorf("Generate() = %+v, want a closed object", s)
+	}
+	if !reflect.DeepEqual(s.Required, []string{"name"}) {
+		t.Errorf("Required = %v, want [name]", s.Required)
+	}
+
+	want := map[string]string{
+		"name":    "a string",
+		"primary": "a string",
+		"shades":  "an array of strings",
+		"weight":  "a number",
+		"count":   "an integer",
+		"public":  "a boolean",
+		"tags":    "an array of strings",
+	}
+	if len(s.Properties) != len(want) {
+		t.Errorf("Properties = %v, want %v", s.Properties, want)
+	}
+	for name, description := range want {
+		if p, ok := s.Properties[name]; !ok || p.Describe() != description {
+			t.Errorf("property %s = %+v, want %s", name, p, description)
+		}
+	}
+	if enum := s.Properties["shades"].Items.Enum; !reflect.DeepEqual(enum, []string{"red", "green"}) {
+		t.Errorf("shades enum = %v", enum)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	output, err := json.Marshal(Document(palette{}, "palette"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document map[string]any
+	if err := json.Unmarshal(output, &document); err != nil {
+		t.Fatal(err)
+	}
+	if document["$schema"] != Dialect || document["title"] != "palette" {
+		t.Errorf("Document() = %s", output)
+	}
+	if _, ok := document["properties"].(map[string]any)["$schema"]; !ok {
+		t.Errorf("Document() does not allow $schema: %s", output)
+	}
+}
+
+func TestSchema_Accepts(t *testing.T) {
+	tests := []struct {
+		schema *Schema
+		value  any
+		want   bool
+	}{
+		{&Schema{Type: "string"}, "x", true},
+		{&Schema{Type: "string"}, json.Number("1"), false},
+		{&Schema{Type: "integer"}, json.Number("1"), true},
+		{&Schema{Type: "integer"}, json.Number("1.5"), false},
+		{&Schema{Type: "number"}, json.Number("1.5"), true},
+		{&Schema{Type: "boolean"}, true, true},
+		{&Schema{Type: "boolean"}, nil, false},
+		{&Schema{}, nil, true},
+	}
+	for _, tt := range tests {
+		if got := tt.schema.Accepts(tt.value); got != tt.want {
+			t.Errorf("%+v.Accepts(%v) = %v, want %v", tt.schema, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// GitConfig returns the value of a git configuration key, or an empty string if git is unavailable or the key is unset.
+func GitConfig(key string) string {
+	out, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// GitTopLevel returns the root of the git working tree containing dir, or an empty string if dir is not in a repository.
+func GitTopLevel(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}